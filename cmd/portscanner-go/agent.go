@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"portscanner-go/internal/proto"
+	"portscanner-go/internal/scanner"
+)
+
+// runServe starts an agent: it listens for controller connections, reads a
+// single ScanRequest off each one, runs the jobs in its shard, and
+// streams a Result back per job followed by a final DoneStats. One
+// connection handles exactly one ScanRequest; the controller opens a new
+// connection per agent for each run.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	listenAddr := fs.String("listen", ":9999", "Address to listen on for controller connections")
+	_ = fs.Parse(args)
+
+	ln, err := net.Listen("tcp", *listenAddr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error: listen:", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "portscanner-go agent listening on %s\n", *listenAddr)
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			continue
+		}
+		go handleControllerConn(conn)
+	}
+}
+
+// handleControllerConn services one controller connection end to end.
+func handleControllerConn(conn net.Conn) {
+	defer conn.Close()
+
+	r := proto.NewReader(conn)
+	w := proto.NewWriter(conn)
+
+	env, err := r.Recv()
+	if err != nil || env.Type != proto.MsgScanRequest || env.Request == nil {
+		return
+	}
+	req := env.Request
+
+	byHost := map[string][]int{}
+	var hostOrder []string
+	for _, j := range req.Jobs {
+		if _, seen := byHost[j.Host]; !seen {
+			hostOrder = append(hostOrder, j.Host)
+		}
+		byHost[j.Host] = append(byHost[j.Host], j.Port)
+	}
+
+	start := time.Now()
+	var completed, open int
+	for _, host := range hostOrder {
+		// Agents run their shard without auth checks or PoCs: a
+		// ScanRequest carries neither (see runController), so nil/nil
+		// here is deliberate, not an oversight.
+		for _, res := range scanner.ScanHostPorts(host, byHost[host], req.Timeout, req.Workers, req.Probe, req.Vuln, nil, nil) {
+			completed++
+			if res.Open {
+				open++
+			}
+			res := res
+			if err := w.Send(proto.Envelope{Type: proto.MsgResult, Result: &res}); err != nil {
+				return
+			}
+		}
+	}
+
+	_ = w.Send(proto.Envelope{
+		Type: proto.MsgDone,
+		Stats: &proto.DoneStats{
+			JobsCompleted: completed,
+			OpenPorts:     open,
+			ElapsedMS:     time.Since(start).Milliseconds(),
+		},
+	})
+}
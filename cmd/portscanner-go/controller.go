@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"portscanner-go/internal/output"
+	"portscanner-go/internal/proto"
+	"portscanner-go/internal/scanner"
+)
+
+// agentState tracks one agent's progress for the live status block and the
+// final summary; it's written from that agent's own goroutine and read from
+// the status renderer, so every access goes through mu.
+type agentState struct {
+	addr string
+
+	mu        sync.Mutex
+	completed int
+	open      int
+	done      bool
+	err       error
+}
+
+func (s *agentState) snapshot() (completed, open int, done bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.completed, s.open, s.done, s.err
+}
+
+// runController shards hosts x ports across agents, dispatches a
+// ScanRequest to each over its own connection, collects the Results each
+// agent streams back, and returns them sorted by host and port. Auth
+// checks and PoCs don't run in controller mode: the wire protocol carries
+// neither authprobe.Options nor []*scanner.POC, so runController prints a
+// warning when -auth or -pocs-dir was given, as it does for a -proto
+// other than tcp.
+func runController(agents []string, hosts []string, ports []int, protoSpec string, timeout time.Duration, workers int, probe, vuln, authCheck, pocsEnabled bool, includeClosed bool) []scanner.Result {
+	if protoSpec != "tcp" {
+		fmt.Fprintf(os.Stderr, "warning: -controller only supports TCP today; ignoring -proto %s and scanning TCP only\n", protoSpec)
+	}
+	if authCheck {
+		fmt.Fprintln(os.Stderr, "warning: -controller does not support -auth yet; credential checks will NOT run on dispatched agents")
+	}
+	if pocsEnabled {
+		fmt.Fprintln(os.Stderr, "warning: -controller does not support -pocs-dir yet; PoCs will NOT run on dispatched agents")
+	}
+
+	var jobs []proto.Job
+	for _, h := range hosts {
+		for _, p := range ports {
+			jobs = append(jobs, proto.Job{Host: h, Port: p, Proto: "tcp"})
+		}
+	}
+	shards := shardJobs(jobs, len(agents))
+
+	states := make([]*agentState, len(agents))
+	for i, addr := range agents {
+		states[i] = &agentState{addr: addr}
+	}
+
+	resultsCh := make(chan scanner.Result, 256)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i, addr := range agents {
+		wg.Add(1)
+		go func(st *agentState, addr string, shard []proto.Job) {
+			defer wg.Done()
+			runAgentShard(st, addr, shard, timeout, workers, probe, vuln, resultsCh)
+		}(states[i], addr, shards[i])
+	}
+	go func() {
+		wg.Wait()
+		close(resultsCh)
+	}()
+
+	stopStatus := make(chan struct{})
+	var statusWg sync.WaitGroup
+	if output.IsTTY(os.Stdout) {
+		statusWg.Add(1)
+		go func() {
+			defer statusWg.Done()
+			renderLiveStatus(os.Stdout, states, len(jobs), start, stopStatus)
+		}()
+	}
+
+	var allResults []scanner.Result
+	for r := range resultsCh {
+		if !includeClosed && !r.Open {
+			continue
+		}
+		allResults = append(allResults, r)
+	}
+	close(stopStatus)
+	statusWg.Wait()
+
+	sort.Slice(allResults, func(i, j int) bool {
+		if allResults[i].Host != allResults[j].Host {
+			return allResults[i].Host < allResults[j].Host
+		}
+		return allResults[i].Port < allResults[j].Port
+	})
+
+	printAgentSummary(os.Stderr, states, time.Since(start))
+	return allResults
+}
+
+// runAgentShard dials one agent, hands it a ScanRequest, and forwards every
+// Result it streams back onto out, updating st as it goes.
+func runAgentShard(st *agentState, addr string, shard []proto.Job, timeout time.Duration, workers int, probe, vuln bool, out chan<- scanner.Result) {
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		st.mu.Lock()
+		st.err, st.done = err, true
+		st.mu.Unlock()
+		return
+	}
+	defer conn.Close()
+
+	w := proto.NewWriter(conn)
+	r := proto.NewReader(conn)
+
+	req := proto.ScanRequest{Jobs: shard, Timeout: timeout, Workers: workers, Probe: probe, Vuln: vuln}
+	if err := w.Send(proto.Envelope{Type: proto.MsgScanRequest, Request: &req}); err != nil {
+		st.mu.Lock()
+		st.err, st.done = err, true
+		st.mu.Unlock()
+		return
+	}
+
+	for {
+		env, err := r.Recv()
+		if err != nil {
+			// A MsgDone always returns below before the stream ends, so
+			// landing here means the connection dropped or desynced
+			// mid-scan — record it as a failure, not a clean finish.
+			st.mu.Lock()
+			st.err, st.done = err, true
+			st.mu.Unlock()
+			return
+		}
+		switch env.Type {
+		case proto.MsgResult:
+			if env.Result == nil {
+				continue
+			}
+			out <- *env.Result
+			st.mu.Lock()
+			st.completed++
+			if env.Result.Open {
+				st.open++
+			}
+			st.mu.Unlock()
+		case proto.MsgDone:
+			st.mu.Lock()
+			st.done = true
+			st.mu.Unlock()
+			return
+		}
+	}
+}
+
+// renderLiveStatus repaints a per-agent progress block in place (via ANSI
+// cursor-up + line-clear) every tick until stop is closed.
+func renderLiveStatus(w *os.File, states []*agentState, totalJobs int, start time.Time, stop <-chan struct{}) {
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	printed := 0
+	draw := func() {
+		if printed > 0 {
+			fmt.Fprintf(w, "\x1b[%dA", printed)
+		}
+		elapsed := time.Since(start).Seconds()
+		completed := 0
+		for _, st := range states {
+			c, open, done, err := st.snapshot()
+			completed += c
+			status := "running"
+			if done {
+				status = "done"
+			}
+			if err != nil {
+				status = "error"
+			}
+			rate := 0.0
+			if elapsed > 0 {
+				rate = float64(c) / elapsed
+			}
+			fmt.Fprintf(w, "\x1b[2K  %-22s %-8s %6d probes  %5d open  %6.1f/s\n", st.addr, status, c, open, rate)
+		}
+		var eta time.Duration
+		if completed > 0 && completed < totalJobs {
+			eta = time.Duration(float64(totalJobs-completed)/float64(completed)*elapsed) * time.Second
+		}
+		fmt.Fprintf(w, "\x1b[2K%d/%d probes complete, ETA %s\n", completed, totalJobs, eta.Round(time.Second))
+		printed = len(states) + 1
+	}
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			draw()
+		}
+	}
+}
+
+// printAgentSummary prints the final per-agent tally once a controller run
+// finishes.
+func printAgentSummary(w *os.File, states []*agentState, total time.Duration) {
+	fmt.Fprintln(w, "\nagent summary:")
+	for _, st := range states {
+		completed, open, _, err := st.snapshot()
+		if err != nil {
+			fmt.Fprintf(w, "  %-22s error: %v\n", st.addr, err)
+			continue
+		}
+		fmt.Fprintf(w, "  %-22s %6d probes, %5d open\n", st.addr, completed, open)
+	}
+	fmt.Fprintf(w, "total wall-clock: %s\n", total.Round(time.Millisecond))
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"portscanner-go/internal/authprobe"
+	"portscanner-go/internal/discovery"
+	"portscanner-go/internal/output"
+	"portscanner-go/internal/ports"
+	"portscanner-go/internal/scanner"
+)
+
+func parsePorts(spec string) ([]int, error) {
+	if spec == "" || strings.HasPrefix(spec, "top:") {
+		n := 100
+		if strings.HasPrefix(spec, "top:") {
+			val := strings.TrimPrefix(spec, "top:")
+			if val != "" {
+				parsed, err := strconv.Atoi(val)
+				if err != nil || parsed <= 0 {
+					return nil, fmt.Errorf("invalid top count: %s", val)
+				}
+				n = parsed
+			}
+		}
+		return ports.Top(n), nil
+	}
+
+	set := map[int]struct{}{}
+	parts := strings.Split(spec, ",")
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if strings.Contains(p, "-") {
+			r := strings.SplitN(p, "-", 2)
+			if len(r) != 2 {
+				return nil, fmt.Errorf("invalid range: %s", p)
+			}
+			start, err1 := strconv.Atoi(strings.TrimSpace(r[0]))
+			end, err2 := strconv.Atoi(strings.TrimSpace(r[1]))
+			if err1 != nil || err2 != nil || start <= 0 || end <= 0 || end < start {
+				return nil, fmt.Errorf("invalid range: %s", p)
+			}
+			for i := start; i <= end; i++ {
+				if i <= 65535 {
+					set[i] = struct{}{}
+				}
+			}
+			continue
+		}
+		val, err := strconv.Atoi(p)
+		if err != nil || val <= 0 || val > 65535 {
+			return nil, fmt.Errorf("invalid port: %s", p)
+		}
+		set[val] = struct{}{}
+	}
+	res := make([]int, 0, len(set))
+	for k := range set {
+		res = append(res, k)
+	}
+	sort.Ints(res)
+	return res, nil
+}
+
+func expandHosts(input string) ([]string, error) {
+	if input == "" {
+		return nil, errors.New("hosts required")
+	}
+	hosts := []string{}
+	items := strings.Split(input, ",")
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if strings.Contains(item, "/") {
+			// CIDR expansion (IPv4)
+			_, ipnet, err := net.ParseCIDR(item)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR: %s", item)
+			}
+			ip := ipnet.IP.To4()
+			if ip == nil {
+				return nil, fmt.Errorf("only IPv4 CIDR supported: %s", item)
+			}
+			mask := ipnet.Mask
+			start := make(net.IP, len(ip))
+			copy(start, ip)
+			end := make(net.IP, len(ip))
+			for i := 0; i < 4; i++ {
+				end[i] = ip[i] | ^mask[i]
+			}
+			for cur := ipToUint32(start); cur <= ipToUint32(end); cur++ {
+				hosts = append(hosts, uint32ToIP(cur).String())
+			}
+			continue
+		}
+		hosts = append(hosts, item)
+	}
+	if len(hosts) == 0 {
+		return nil, errors.New("no valid hosts provided")
+	}
+	return hosts, nil
+}
+
+// shouldAutoDiscover reports whether hostsSpec contains a CIDR range
+// large enough (bigger than a /24) that a discovery sweep is worth the
+// cost of skipping dead hosts before port-scanning them.
+func shouldAutoDiscover(hostsSpec string) bool {
+	for _, item := range strings.Split(hostsSpec, ",") {
+		item = strings.TrimSpace(item)
+		if !strings.Contains(item, "/") {
+			continue
+		}
+		_, ipnet, err := net.ParseCIDR(item)
+		if err != nil {
+			continue
+		}
+		ones, bits := ipnet.Mask.Size()
+		if bits-ones > 8 {
+			return true
+		}
+	}
+	return false
+}
+
+func ipToUint32(ip net.IP) uint32 {
+	ip4 := ip.To4()
+	return uint32(ip4[0])<<24 | uint32(ip4[1])<<16 | uint32(ip4[2])<<8 | uint32(ip4[3])
+}
+
+func uint32ToIP(n uint32) net.IP {
+	return net.IPv4(byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(os.Args[2:])
+		return
+	}
+
+	var hostsSpec string
+	var portsSpec string
+	var timeoutStr string
+	var workers int
+	var jsonOut bool
+	var includeClosed bool
+	var probe bool
+	var vulnScan bool
+	var authCheck bool
+	var anonOnly bool
+	var userlistPath string
+	var passlistPath string
+	var sshKeysSpec string
+	var protoSpec string
+	var discoverFlag bool
+	var pocsDir string
+	var controllerSpec string
+
+	flag.StringVar(&hostsSpec, "hosts", "", "Target hosts: comma-separated or CIDR (IPv4)")
+	flag.StringVar(&portsSpec, "ports", "top:100", "Ports: e.g. 'top:100' or '1-1024,80,443'")
+	flag.StringVar(&timeoutStr, "timeout", "500ms", "Dial timeout per port, e.g. 500ms, 1s")
+	flag.IntVar(&workers, "workers", 500, "Concurrent workers")
+	flag.BoolVar(&jsonOut, "json", false, "Output JSON")
+	flag.BoolVar(&includeClosed, "all", false, "Include closed ports in output")
+	flag.BoolVar(&probe, "probe", true, "Fingerprint services on open ports")
+	flag.BoolVar(&vulnScan, "vuln", false, "Run safe, read-only vulnerability probes against identified services")
+	flag.BoolVar(&authCheck, "auth", false, "Run low-volume credential checks against identified auth services")
+	flag.BoolVar(&anonOnly, "anon-only", false, "With -auth, only test unauthenticated/anonymous access, no credential guessing")
+	flag.StringVar(&userlistPath, "userlist", "", "With -auth, path to a newline-delimited username wordlist")
+	flag.StringVar(&passlistPath, "passlist", "", "With -auth, path to a newline-delimited password wordlist")
+	flag.StringVar(&sshKeysSpec, "ssh-keys", "", "With -auth, comma-separated paths to PEM private keys to try for SSH public-key auth")
+	flag.StringVar(&protoSpec, "proto", "tcp", "Protocol(s) to scan: tcp, udp, or both")
+	flag.BoolVar(&discoverFlag, "discover", false, "Sweep for live hosts (ICMP/ARP/NetBIOS) before scanning; auto-enabled for CIDR ranges bigger than a /24")
+	flag.StringVar(&pocsDir, "pocs-dir", "", "Directory of YAML PoC templates to run against fingerprinted web services")
+	flag.StringVar(&controllerSpec, "controller", "", "Comma-separated addrs of 'portscanner-go serve' agents to dispatch this scan to, instead of running it locally")
+	flag.Parse()
+
+	switch protoSpec {
+	case "tcp", "udp", "both":
+	default:
+		fmt.Fprintln(os.Stderr, "error: -proto must be tcp, udp, or both")
+		os.Exit(1)
+	}
+
+	hosts, err := expandHosts(hostsSpec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+	portsList, err := parsePorts(portsSpec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+	timeout, err := time.ParseDuration(timeoutStr)
+	if err != nil || timeout <= 0 {
+		fmt.Fprintln(os.Stderr, "error: invalid timeout")
+		os.Exit(1)
+	}
+	if workers <= 0 {
+		workers = 100
+	}
+	if vulnScan {
+		probe = true
+	}
+
+	var authOpts *authprobe.Options
+	if authCheck {
+		probe = true
+		authOpts = authprobe.DefaultOptions(anonOnly)
+		if userlistPath != "" {
+			users, err := authprobe.LoadWordlist(userlistPath)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "error: userlist:", err)
+				os.Exit(1)
+			}
+			authOpts.Users = users
+		}
+		if passlistPath != "" {
+			passwords, err := authprobe.LoadWordlist(passlistPath)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "error: passlist:", err)
+				os.Exit(1)
+			}
+			authOpts.Passwords = passwords
+		}
+		if sshKeysSpec != "" {
+			signers, err := authprobe.LoadSSHKeys(strings.Split(sshKeysSpec, ","))
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "warning: ssh-keys:", err)
+			}
+			authOpts.Signers = signers
+		}
+	}
+
+	var pocs []*scanner.POC
+	if pocsDir != "" {
+		probe = true
+		loaded, err := scanner.LoadPOCs(pocsDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "warning: pocs-dir:", err)
+		}
+		pocs = loaded
+	}
+
+	discovered := map[string]discovery.HostInfo{}
+	if discoverFlag || shouldAutoDiscover(hostsSpec) {
+		found := discovery.Sweep(hosts, timeout)
+		live := make([]string, 0, len(found))
+		for _, h := range found {
+			discovered[h.IP] = h
+			live = append(live, h.IP)
+		}
+		if len(live) > 0 {
+			hosts = live
+		} else {
+			fmt.Fprintln(os.Stderr, "warning: discovery found no live hosts; scanning original target list")
+		}
+	}
+
+	keep := func(r scanner.Result) bool {
+		if includeClosed {
+			return true
+		}
+		if r.Proto == "udp" {
+			return r.State != "closed"
+		}
+		return r.Open
+	}
+
+	allResults := []scanner.Result{}
+	if controllerSpec != "" {
+		agents := strings.Split(controllerSpec, ",")
+		allResults = runController(agents, hosts, portsList, protoSpec, timeout, workers, probe, vulnScan, authCheck, pocsDir != "", includeClosed)
+		for i, r := range allResults {
+			if info, ok := discovered[r.Host]; ok {
+				allResults[i].Hostname = info.Hostname
+				allResults[i].MAC = info.MAC
+				allResults[i].Vendor = info.Vendor
+			}
+		}
+	} else {
+		for _, h := range hosts {
+			var results []scanner.Result
+			if protoSpec == "tcp" || protoSpec == "both" {
+				results = append(results, scanner.ScanHostPorts(h, portsList, timeout, workers, probe, vulnScan, authOpts, pocs)...)
+			}
+			if protoSpec == "udp" || protoSpec == "both" {
+				results = append(results, scanner.ScanHostPortsUDP(h, portsList, timeout, workers, probe)...)
+			}
+			filtered := make([]scanner.Result, 0, len(results))
+			for _, r := range results {
+				if !keep(r) {
+					continue
+				}
+				if info, ok := discovered[r.Host]; ok {
+					r.Hostname = info.Hostname
+					r.MAC = info.MAC
+					r.Vendor = info.Vendor
+				}
+				filtered = append(filtered, r)
+			}
+			allResults = append(allResults, filtered...)
+		}
+	}
+
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(allResults)
+		return
+	}
+
+	output.PrintTable(os.Stdout, allResults)
+}
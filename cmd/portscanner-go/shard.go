@@ -0,0 +1,23 @@
+package main
+
+import (
+	"hash/fnv"
+	"strconv"
+
+	"portscanner-go/internal/proto"
+)
+
+// shardJobs partitions jobs across numAgents buckets by hashing
+// "host:port". Hashing rather than round-robin means the same pair always
+// lands on the same agent, which keeps a retried controller run stable and
+// spreads load evenly regardless of how the job list is ordered.
+func shardJobs(jobs []proto.Job, numAgents int) [][]proto.Job {
+	shards := make([][]proto.Job, numAgents)
+	for _, j := range jobs {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(j.Host + ":" + strconv.Itoa(j.Port)))
+		idx := int(h.Sum32() % uint32(numAgents))
+		shards[idx] = append(shards[idx], j)
+	}
+	return shards
+}
@@ -0,0 +1,86 @@
+// Package authprobe performs low-volume, opt-in credential checks against
+// common auth-bearing services that the scanner has already fingerprinted.
+// Every probe is capped in how many credential pairs it will try and
+// shares a single rate limiter across the whole scan, so enabling this
+// never turns a port scan into a brute-force run.
+package authprobe
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// maxAttempts caps how many credential pairs a single Check call will try
+// against one service instance, to avoid tripping account lockouts.
+const maxAttempts = 5
+
+// Options configures the probes for a scan. A single Options (and its
+// embedded Limiter) should be shared by every worker so the attempt rate
+// is throttled across the whole scan, not per host.
+type Options struct {
+	Users     []string
+	Passwords []string
+	Signers   []ssh.Signer // with -auth, additional SSH public keys to try alongside password guessing
+	AnonOnly  bool
+	Limiter   *RateLimiter
+}
+
+// DefaultOptions returns Options using the built-in short wordlists and a
+// conservative shared rate limit.
+func DefaultOptions(anonOnly bool) *Options {
+	return &Options{
+		Users:     DefaultUsers,
+		Passwords: DefaultPasswords,
+		AnonOnly:  anonOnly,
+		Limiter:   NewRateLimiter(10, time.Second),
+	}
+}
+
+type probeFunc func(host string, port int, timeout time.Duration, opts *Options) (state string, creds string)
+
+var probes = map[string]probeFunc{}
+
+func register(service string, fn probeFunc) {
+	probes[service] = fn
+}
+
+// Check dispatches to the probe registered for service and returns the
+// resulting AuthState ("open", "anon", "weak-creds") and a redacted
+// credentials string, or ("", "") if the service isn't supported or the
+// probe found nothing.
+func Check(service, host string, port int, timeout time.Duration, opts *Options) (string, string) {
+	if opts == nil {
+		return "", ""
+	}
+	probe, ok := probes[service]
+	if !ok {
+		return "", ""
+	}
+	return probe(host, port, timeout, opts)
+}
+
+// jitterBackoff sleeps a small randomized delay between credential
+// attempts so checks don't look like a tight brute-force loop.
+func jitterBackoff() {
+	time.Sleep(time.Duration(150+rand.Intn(250)) * time.Millisecond)
+}
+
+// redact formats a matched credential pair for display without leaking
+// the full secret.
+func redact(user, pass string) string {
+	label := user
+	if label == "" {
+		label = "(none)"
+	}
+	switch {
+	case pass == "":
+		return label + ":(blank)"
+	case len(pass) <= 2:
+		return label + ":**"
+	default:
+		return label + ":" + pass[:1] + strings.Repeat("*", len(pass)-2) + pass[len(pass)-1:]
+	}
+}
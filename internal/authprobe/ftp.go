@@ -0,0 +1,41 @@
+package authprobe
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+func init() {
+	register("ftp", ftpCheck)
+}
+
+// ftpCheck tries the anonymous FTP login (RFC 1635): USER anonymous /
+// PASS <email-looking string>, accepted if the server replies 230.
+func ftpCheck(host string, port int, timeout time.Duration, opts *Options) (string, string) {
+	if !opts.AnonOnly && len(opts.Users) == 0 {
+		return "", ""
+	}
+	addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return "", ""
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	r := bufio.NewReader(conn)
+	_, _ = r.ReadString('\n') // banner
+
+	opts.Limiter.Wait()
+	fmt.Fprint(conn, "USER anonymous\r\n")
+	_, _ = r.ReadString('\n')
+	fmt.Fprint(conn, "PASS anonymous@\r\n")
+	line, err := r.ReadString('\n')
+	if err == nil && strings.HasPrefix(line, "230") {
+		return "anon", "anonymous:anonymous@"
+	}
+	return "", ""
+}
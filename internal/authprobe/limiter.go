@@ -0,0 +1,37 @@
+package authprobe
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter is a simple shared token bucket used to cap credential
+// attempts/sec across every worker in a scan.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter returns a limiter allowing n events per duration.
+func NewRateLimiter(n int, per time.Duration) *RateLimiter {
+	if n <= 0 {
+		n = 1
+	}
+	return &RateLimiter{interval: per / time.Duration(n)}
+}
+
+// Wait blocks until the next attempt is allowed.
+func (r *RateLimiter) Wait() {
+	r.mu.Lock()
+	now := time.Now()
+	if now.Before(r.next) {
+		wait := r.next.Sub(now)
+		r.next = r.next.Add(r.interval)
+		r.mu.Unlock()
+		time.Sleep(wait)
+		return
+	}
+	r.next = now.Add(r.interval)
+	r.mu.Unlock()
+}
@@ -0,0 +1,76 @@
+package authprobe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+func init() {
+	register("mongodb", mongoCheck)
+}
+
+// mongoCheck sends an OP_QUERY {isMaster: 1} against admin.$cmd. A server
+// with no authentication enabled answers directly; this is enough to
+// flag it as open without attempting any credentials.
+func mongoCheck(host string, port int, timeout time.Duration, opts *Options) (string, string) {
+	addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return "", ""
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	opts.Limiter.Wait()
+	if _, err := conn.Write(mongoOpQuery("admin.$cmd", bsonIsMaster())); err != nil {
+		return "", ""
+	}
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil || n < 16 {
+		return "", ""
+	}
+	// Heuristic: a successful isMaster reply BSON-encodes "ismaster" true
+	// and "ok" 1.0 without ever asking for credentials.
+	if bytes.Contains(buf[:n], []byte("ismaster")) {
+		return "open", ""
+	}
+	return "", ""
+}
+
+// mongoOpQuery wraps a BSON query document in a minimal OP_QUERY message.
+func mongoOpQuery(fullCollectionName string, doc []byte) []byte {
+	body := new(bytes.Buffer)
+	binary.Write(body, binary.LittleEndian, int32(0)) // flags
+	body.WriteString(fullCollectionName)
+	body.WriteByte(0)
+	binary.Write(body, binary.LittleEndian, int32(0)) // numberToSkip
+	binary.Write(body, binary.LittleEndian, int32(1)) // numberToReturn
+	body.Write(doc)
+
+	msg := new(bytes.Buffer)
+	binary.Write(msg, binary.LittleEndian, int32(16+body.Len())) // messageLength
+	binary.Write(msg, binary.LittleEndian, int32(1))              // requestID
+	binary.Write(msg, binary.LittleEndian, int32(0))               // responseTo
+	binary.Write(msg, binary.LittleEndian, int32(2004))            // opCode: OP_QUERY
+	msg.Write(body.Bytes())
+	return msg.Bytes()
+}
+
+// bsonIsMaster returns the BSON document {"isMaster": 1}.
+func bsonIsMaster() []byte {
+	field := new(bytes.Buffer)
+	field.WriteByte(0x10) // int32
+	field.WriteString("isMaster")
+	field.WriteByte(0)
+	binary.Write(field, binary.LittleEndian, int32(1))
+
+	doc := new(bytes.Buffer)
+	binary.Write(doc, binary.LittleEndian, int32(5+field.Len()))
+	doc.Write(field.Bytes())
+	doc.WriteByte(0)
+	return doc.Bytes()
+}
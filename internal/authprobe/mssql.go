@@ -0,0 +1,163 @@
+package authprobe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+func init() {
+	register("mssql", mssqlCheck)
+}
+
+// mssqlCheck completes a TDS PRELOGIN handshake, then sends a LOGIN7
+// packet for each candidate user/password and inspects the response
+// stream for a LOGINACK token (0xAD, success) versus an ERROR token
+// (0xAA, rejected).
+func mssqlCheck(host string, port int, timeout time.Duration, opts *Options) (string, string) {
+	if opts.AnonOnly {
+		return "", ""
+	}
+	addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+
+	attempts := 0
+	for _, user := range opts.Users {
+		for _, pass := range opts.Passwords {
+			if attempts >= maxAttempts {
+				return "", ""
+			}
+			attempts++
+			conn, err := net.DialTimeout("tcp", addr, timeout)
+			if err != nil {
+				return "", ""
+			}
+			_ = conn.SetDeadline(time.Now().Add(timeout))
+			opts.Limiter.Wait()
+
+			if _, err := conn.Write(tdsPacket(0x12, []byte{
+				// minimal PRELOGIN option stream: VERSION then TERMINATOR
+				0x00, 0x00, 0x06, 0x00, 0x06, 0xFF,
+				0x09, 0x00, 0x00, 0x00, 0x00, 0x00,
+			})); err != nil {
+				conn.Close()
+				continue
+			}
+			_, _ = readTDSPacket(conn)
+
+			if _, err := conn.Write(tdsPacket(0x10, tdsLogin7(user, pass))); err != nil {
+				conn.Close()
+				continue
+			}
+			resp, err := readTDSPacket(conn)
+			conn.Close()
+			if err != nil {
+				continue
+			}
+			if bytes.IndexByte(resp, 0xAD) >= 0 {
+				return "weak-creds", redact(user, pass)
+			}
+			jitterBackoff()
+		}
+	}
+	return "", ""
+}
+
+func tdsPacket(packetType byte, data []byte) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(packetType)
+	buf.WriteByte(0x01) // status: EOM
+	binary.Write(buf, binary.BigEndian, uint16(8+len(data)))
+	binary.Write(buf, binary.BigEndian, uint16(0)) // SPID
+	buf.WriteByte(0)                               // PacketID
+	buf.WriteByte(0)                               // Window
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func readTDSPacket(conn net.Conn) ([]byte, error) {
+	hdr := make([]byte, 8)
+	if _, err := fullReadPG(conn, hdr); err != nil {
+		return nil, err
+	}
+	l := binary.BigEndian.Uint16(hdr[2:4])
+	if l <= 8 {
+		return nil, nil
+	}
+	body := make([]byte, l-8)
+	if _, err := fullReadPG(conn, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// tdsLogin7 builds a minimal LOGIN7 packet authenticating with SQL
+// Server auth (not Windows/NTLM) for the given credentials.
+func tdsLogin7(user, pass string) []byte {
+	userUTF16 := toUTF16LE(user)
+	passUTF16 := tdsObfuscatePassword(pass)
+	appName := toUTF16LE("portscanner-go")
+
+	const fixedLen = 36   // LOGIN7 fixed header length up to the offset/length block
+	const offsetsLen = 58 // offset/length block, including the 6-byte ClientID
+	pos := uint16(fixedLen + offsetsLen)
+
+	offsets := new(bytes.Buffer)
+	data := new(bytes.Buffer)
+	addField := func(b []byte) {
+		binary.Write(offsets, binary.LittleEndian, pos)
+		binary.Write(offsets, binary.LittleEndian, uint16(len(b)/2))
+		pos += uint16(len(b))
+		data.Write(b)
+	}
+
+	hdr := new(bytes.Buffer)
+	binary.Write(hdr, binary.LittleEndian, uint32(0))        // Length, patched below
+	binary.Write(hdr, binary.LittleEndian, uint32(0x74000004)) // TDS version
+	binary.Write(hdr, binary.LittleEndian, uint32(4096))      // PacketSize
+	hdr.Write(make([]byte, fixedLen-12))                      // remaining fixed fields, zeroed
+
+	addField([]byte{})  // Hostname
+	addField(userUTF16)
+	addField(passUTF16)
+	addField(appName)
+	addField([]byte{}) // ServerName
+	addField([]byte{}) // Extension
+	addField([]byte{}) // CltIntName
+	addField([]byte{}) // Language
+	addField([]byte{}) // Database
+	offsets.Write(make([]byte, 6)) // ClientID
+	addField([]byte{})             // SSPI
+	addField([]byte{})             // AtchDBFile
+	addField([]byte{})             // ChangePassword
+	binary.Write(offsets, binary.LittleEndian, uint32(0)) // cbSSPILong
+
+	full := new(bytes.Buffer)
+	full.Write(hdr.Bytes())
+	full.Write(offsets.Bytes())
+	full.Write(data.Bytes())
+
+	out := full.Bytes()
+	binary.LittleEndian.PutUint32(out[0:4], uint32(len(out)))
+	return out
+}
+
+func toUTF16LE(s string) []byte {
+	out := make([]byte, 0, len(s)*2)
+	for _, r := range s {
+		out = append(out, byte(r), byte(r>>8))
+	}
+	return out
+}
+
+// tdsObfuscatePassword applies the trivial TDS password XOR/nibble-swap
+// obfuscation (not encryption) used on the wire.
+func tdsObfuscatePassword(pass string) []byte {
+	b := toUTF16LE(pass)
+	for i, c := range b {
+		c ^= 0xA5
+		b[i] = (c << 4) | (c >> 4)
+	}
+	return b
+}
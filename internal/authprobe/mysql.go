@@ -0,0 +1,159 @@
+package authprobe
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net"
+	"time"
+)
+
+func init() {
+	register("mysql", mysqlCheck)
+}
+
+// mysqlCheck reads the server's initial handshake packet, then tries each
+// user/password pair from the wordlists via the mysql_native_password
+// scramble (an empty password in the list is sent as a zero-length auth
+// response, same as a blank password). A generic OK packet (0x00) means
+// the credential pair was accepted.
+func mysqlCheck(host string, port int, timeout time.Duration, opts *Options) (string, string) {
+	if opts.AnonOnly {
+		return "", ""
+	}
+	addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+
+	attempts := 0
+	for _, user := range opts.Users {
+		for _, pass := range opts.Passwords {
+			if attempts >= maxAttempts {
+				return "", ""
+			}
+			attempts++
+			conn, err := net.DialTimeout("tcp", addr, timeout)
+			if err != nil {
+				return "", ""
+			}
+			opts.Limiter.Wait()
+			ok := mysqlTryLogin(conn, user, pass, timeout)
+			conn.Close()
+			if ok {
+				return "weak-creds", redact(user, pass)
+			}
+			jitterBackoff()
+		}
+	}
+	return "", ""
+}
+
+func mysqlTryLogin(conn net.Conn, user, pass string, timeout time.Duration) bool {
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	handshake := make([]byte, 1024)
+	n, err := conn.Read(handshake)
+	if err != nil || n < 5 {
+		return false
+	}
+	seq := handshake[3] + 1
+
+	scramble, ok := mysqlParseHandshakeScramble(handshake[4:n])
+	if !ok {
+		return false
+	}
+	auth := mysqlNativePasswordAuth(pass, scramble)
+
+	payload := make([]byte, 0, 32+len(user)+len(auth))
+	payload = append(payload, 0x85, 0xA6, 0x03, 0x00) // client capability flags
+	payload = append(payload, 0x00, 0x00, 0x00, 0x01) // max packet size
+	payload = append(payload, 0x21)                   // charset: utf8_general_ci
+	payload = append(payload, make([]byte, 23)...)    // reserved
+	payload = append(payload, []byte(user)...)
+	payload = append(payload, 0x00)
+	payload = append(payload, byte(len(auth)))
+	payload = append(payload, auth...)
+
+	pkt := mysqlPacket(payload, seq)
+	if _, err := conn.Write(pkt); err != nil {
+		return false
+	}
+
+	resp := make([]byte, 256)
+	n, err = conn.Read(resp)
+	if err != nil || n < 5 {
+		return false
+	}
+	return resp[4] == 0x00 // OK packet
+}
+
+// mysqlParseHandshakeScramble pulls the 20-byte auth-plugin-data
+// ("scramble") out of a v10 handshake payload: an 8-byte part right
+// before the filler/capability/charset/status fields, and a further
+// (at least) 12-byte part after them, per the MySQL protocol's
+// Protocol::HandshakeV10.
+func mysqlParseHandshakeScramble(payload []byte) ([]byte, bool) {
+	i := 1 // protocol version
+	nul := -1
+	for j := i; j < len(payload); j++ {
+		if payload[j] == 0x00 {
+			nul = j
+			break
+		}
+	}
+	if nul < 0 {
+		return nil, false
+	}
+	i = nul + 1 // past the null-terminated server version string
+
+	// connection id (4) + auth-plugin-data-part-1 (8)
+	if i+4+8 > len(payload) {
+		return nil, false
+	}
+	i += 4
+	part1 := payload[i : i+8]
+	i += 8
+
+	// filler(1) + capability flags lower(2) + charset(1) + status(2) +
+	// capability flags upper(2) + auth_plugin_data_len(1) + reserved(10)
+	const skip = 1 + 2 + 1 + 2 + 2
+	if i+skip+1+10 > len(payload) {
+		return nil, false
+	}
+	i += skip
+	i++  // auth_plugin_data_len, unused: part2 is always read as 12 bytes
+	i += 10
+
+	if i+12 > len(payload) {
+		return nil, false
+	}
+	part2 := payload[i : i+12]
+
+	scramble := make([]byte, 0, 20)
+	scramble = append(scramble, part1...)
+	scramble = append(scramble, part2...)
+	return scramble, true
+}
+
+// mysqlNativePasswordAuth computes the mysql_native_password auth
+// response: SHA1(password) XOR SHA1(scramble + SHA1(SHA1(password))).
+// An empty password yields a zero-length response, per protocol.
+func mysqlNativePasswordAuth(password string, scramble []byte) []byte {
+	if password == "" {
+		return nil
+	}
+	stage1 := sha1.Sum([]byte(password))
+	stage2 := sha1.Sum(stage1[:])
+	h := sha1.New()
+	h.Write(scramble)
+	h.Write(stage2[:])
+	stage3 := h.Sum(nil)
+
+	token := make([]byte, len(stage1))
+	for i := range token {
+		token[i] = stage1[i] ^ stage3[i]
+	}
+	return token
+}
+
+func mysqlPacket(payload []byte, seq byte) []byte {
+	l := len(payload)
+	return append([]byte{byte(l), byte(l >> 8), byte(l >> 16), seq}, payload...)
+}
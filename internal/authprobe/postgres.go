@@ -0,0 +1,179 @@
+package authprobe
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"time"
+)
+
+func init() {
+	register("postgresql", postgresCheck)
+}
+
+// postgresCheck sends a startup message per candidate user and inspects
+// the authentication request: type 0 (AuthenticationOk) with no password
+// exchange means trust auth is enabled; type 3 (cleartext) or 5 (MD5)
+// are answered with the matching PasswordMessage and checked for
+// AuthenticationOk in the follow-up reply. Any other auth type (e.g.
+// SCRAM) isn't implemented, so that user is skipped rather than sent a
+// password response the server will never accept.
+func postgresCheck(host string, port int, timeout time.Duration, opts *Options) (string, string) {
+	addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+
+	attempts := 0
+	for _, user := range opts.Users {
+		conn, err := net.DialTimeout("tcp", addr, timeout)
+		if err != nil {
+			return "", ""
+		}
+		_ = conn.SetDeadline(time.Now().Add(timeout))
+		opts.Limiter.Wait()
+		if _, err := conn.Write(pgStartupMessage(user)); err != nil {
+			conn.Close()
+			continue
+		}
+
+		authType, _, ok := pgReadAuthRequest(conn)
+		if ok && authType == 0 {
+			conn.Close()
+			return "open", redact(user, "")
+		}
+		conn.Close()
+		if opts.AnonOnly || !ok || (authType != 3 && authType != 5) {
+			continue
+		}
+
+		for _, pass := range opts.Passwords {
+			if attempts >= maxAttempts {
+				return "", ""
+			}
+			attempts++
+			c2, err := net.DialTimeout("tcp", addr, timeout)
+			if err != nil {
+				return "", ""
+			}
+			_ = c2.SetDeadline(time.Now().Add(timeout))
+			opts.Limiter.Wait()
+			_, _ = c2.Write(pgStartupMessage(user))
+			at, salt, ok := pgReadAuthRequest(c2)
+			if !ok {
+				c2.Close()
+				jitterBackoff()
+				continue
+			}
+			resp, supported := pgPasswordResponse(at, user, pass, salt)
+			if !supported {
+				c2.Close()
+				continue
+			}
+			_, _ = c2.Write(resp)
+			final, _, ok := pgReadAuthRequest(c2)
+			c2.Close()
+			if ok && final == 0 {
+				return "weak-creds", redact(user, pass)
+			}
+			jitterBackoff()
+		}
+	}
+	return "", ""
+}
+
+func pgStartupMessage(user string) []byte {
+	params := new(bytes.Buffer)
+	params.WriteString("user")
+	params.WriteByte(0)
+	params.WriteString(user)
+	params.WriteByte(0)
+	params.WriteString("database")
+	params.WriteByte(0)
+	params.WriteString(user)
+	params.WriteByte(0)
+	params.WriteByte(0)
+
+	msg := new(bytes.Buffer)
+	binary.Write(msg, binary.BigEndian, int32(8+params.Len()))
+	binary.Write(msg, binary.BigEndian, int32(196608)) // protocol version 3.0
+	msg.Write(params.Bytes())
+	return msg.Bytes()
+}
+
+func pgPasswordMessage(pass string) []byte {
+	msg := new(bytes.Buffer)
+	msg.WriteByte('p')
+	binary.Write(msg, binary.BigEndian, int32(4+len(pass)+1))
+	msg.WriteString(pass)
+	msg.WriteByte(0)
+	return msg.Bytes()
+}
+
+// pgPasswordResponse builds the PasswordMessage for the auth type the
+// server actually requested: cleartext (3) sends pass as-is, MD5 (5)
+// sends Postgres's "md5"+md5(md5(pass+user)+salt) challenge response.
+// Any other auth type (SCRAM, GSS, ...) isn't implemented and reports
+// !supported so the caller can skip it instead of guessing wrong.
+func pgPasswordResponse(authType int32, user, pass string, salt []byte) ([]byte, bool) {
+	switch authType {
+	case 3:
+		return pgPasswordMessage(pass), true
+	case 5:
+		if len(salt) < 4 {
+			return nil, false
+		}
+		return pgPasswordMessage(pgMD5Digest(user, pass, salt[:4])), true
+	default:
+		return nil, false
+	}
+}
+
+// pgMD5Digest implements Postgres's md5 auth challenge-response:
+// "md5" + hex(md5(hex(md5(pass+user)) + salt)).
+func pgMD5Digest(user, pass string, salt []byte) string {
+	inner := md5Hex(pass + user)
+	h := md5.New()
+	h.Write([]byte(inner))
+	h.Write(salt)
+	return "md5" + hex.EncodeToString(h.Sum(nil))
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// pgReadAuthRequest reads one backend message and, if it's an
+// AuthenticationRequest ('R'), returns its auth type code and any bytes
+// following it (e.g. the 4-byte MD5 salt for type 5).
+func pgReadAuthRequest(conn net.Conn) (int32, []byte, bool) {
+	hdr := make([]byte, 5)
+	if _, err := fullReadPG(conn, hdr); err != nil {
+		return 0, nil, false
+	}
+	if hdr[0] != 'R' {
+		return 0, nil, false
+	}
+	l := binary.BigEndian.Uint32(hdr[1:5])
+	if l <= 4 {
+		return 0, nil, false
+	}
+	body := make([]byte, l-4)
+	if _, err := fullReadPG(conn, body); err != nil || len(body) < 4 {
+		return 0, nil, false
+	}
+	return int32(binary.BigEndian.Uint32(body[:4])), body[4:], true
+}
+
+func fullReadPG(conn net.Conn, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := conn.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,59 @@
+package authprobe
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+func init() {
+	register("redis", redisCheck)
+}
+
+// redisCheck first tries an unauthenticated CONFIG GET (an open instance
+// answers without ever seeing AUTH); failing that, it tries AUTH with
+// each candidate password.
+func redisCheck(host string, port int, timeout time.Duration, opts *Options) (string, string) {
+	addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return "", ""
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	if reply, err := redisCommand(conn, "CONFIG GET maxmemory"); err == nil && strings.HasPrefix(reply, "*") {
+		return "open", ""
+	}
+	if opts.AnonOnly {
+		return "", ""
+	}
+
+	attempts := 0
+	for _, pass := range opts.Passwords {
+		if attempts >= maxAttempts {
+			break
+		}
+		attempts++
+		opts.Limiter.Wait()
+		reply, err := redisCommand(conn, fmt.Sprintf("AUTH %s", pass))
+		if err == nil && strings.HasPrefix(reply, "+OK") {
+			return "weak-creds", redact("", pass)
+		}
+		jitterBackoff()
+	}
+	return "", ""
+}
+
+func redisCommand(conn net.Conn, cmd string) (string, error) {
+	if _, err := conn.Write([]byte(cmd + "\r\n")); err != nil {
+		return "", err
+	}
+	buf := make([]byte, 512)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return string(buf[:n]), nil
+}
@@ -0,0 +1,115 @@
+package authprobe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+func init() {
+	register("smb", smbCheck)
+}
+
+// smbCheck attempts an SMB1 null session: negotiate, then session setup
+// with a zero-length anonymous credential. A non-error status on the
+// session setup response means the host allows anonymous sessions.
+func smbCheck(host string, port int, timeout time.Duration, opts *Options) (string, string) {
+	if !opts.AnonOnly && len(opts.Users) == 0 {
+		return "", ""
+	}
+	addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return "", ""
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	opts.Limiter.Wait()
+	if _, err := conn.Write(nbssWrap(smbNegotiateRequest())); err != nil {
+		return "", ""
+	}
+	if _, err := readNBSS(conn); err != nil {
+		return "", ""
+	}
+
+	if _, err := conn.Write(nbssWrap(smbNullSessionSetupRequest())); err != nil {
+		return "", ""
+	}
+	resp, err := readNBSS(conn)
+	if err != nil || len(resp) < 9 {
+		return "", ""
+	}
+	status := binary.LittleEndian.Uint32(resp[5:9])
+	if status == 0 {
+		return "anon", "(null session)"
+	}
+	return "", ""
+}
+
+func nbssWrap(payload []byte) []byte {
+	out := make([]byte, 4+len(payload))
+	l := uint32(len(payload))
+	out[1], out[2], out[3] = byte(l>>16), byte(l>>8), byte(l)
+	copy(out[4:], payload)
+	return out
+}
+
+func readNBSS(conn net.Conn) ([]byte, error) {
+	hdr := make([]byte, 4)
+	if _, err := fullReadPG(conn, hdr); err != nil {
+		return nil, err
+	}
+	l := uint32(hdr[1])<<16 | uint32(hdr[2])<<8 | uint32(hdr[3])
+	body := make([]byte, l)
+	if _, err := fullReadPG(conn, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func smbHeader(command byte) []byte {
+	buf := make([]byte, 32)
+	copy(buf[0:4], []byte{0xFF, 'S', 'M', 'B'})
+	buf[4] = command
+	return buf
+}
+
+func smbNegotiateRequest() []byte {
+	hdr := smbHeader(0x72)
+	body := new(bytes.Buffer)
+	body.WriteByte(0)
+	dialects := []string{"NT LM 0.12"}
+	var names bytes.Buffer
+	for _, d := range dialects {
+		names.WriteByte(0x02)
+		names.WriteString(d)
+		names.WriteByte(0x00)
+	}
+	binary.Write(body, binary.LittleEndian, uint16(names.Len()))
+	body.Write(names.Bytes())
+	return append(hdr, body.Bytes()...)
+}
+
+// smbNullSessionSetupRequest sends an SMB1 SessionSetupAndX with zero
+// length OEM/Unicode password blocks, i.e. an anonymous logon attempt.
+func smbNullSessionSetupRequest() []byte {
+	hdr := smbHeader(0x73)
+	body := new(bytes.Buffer)
+	body.WriteByte(13)
+	body.WriteByte(0xFF) // AndXCommand: none
+	body.WriteByte(0)
+	binary.Write(body, binary.LittleEndian, uint16(0))    // AndXOffset
+	binary.Write(body, binary.LittleEndian, uint16(4356)) // MaxBufferSize
+	binary.Write(body, binary.LittleEndian, uint16(2))    // MaxMpxCount
+	binary.Write(body, binary.LittleEndian, uint16(0))    // VcNumber
+	binary.Write(body, binary.LittleEndian, uint32(0))    // SessionKey
+	binary.Write(body, binary.LittleEndian, uint16(0))    // OEMPasswordLen
+	binary.Write(body, binary.LittleEndian, uint16(0))    // UnicodePasswordLen
+	body.Write(make([]byte, 4))                           // Reserved
+	binary.Write(body, binary.LittleEndian, uint32(0))    // Capabilities
+	binary.Write(body, binary.LittleEndian, uint16(0))    // ByteCount: no names
+	return append(hdr, body.Bytes()...)
+}
@@ -0,0 +1,91 @@
+package authprobe
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func init() {
+	register("ssh", sshCheck)
+}
+
+// sshCheck tries public-key auth with each of opts.Signers, then password
+// auth from the wordlists, per candidate user. It stops at the first
+// success or once maxAttempts credential pairs have been tried.
+func sshCheck(host string, port int, timeout time.Duration, opts *Options) (string, string) {
+	if opts.AnonOnly {
+		return "", ""
+	}
+	addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+
+	attempts := 0
+	for _, user := range opts.Users {
+		for _, signer := range opts.Signers {
+			if attempts >= maxAttempts {
+				return "", ""
+			}
+			attempts++
+			opts.Limiter.Wait()
+			cfg := &ssh.ClientConfig{
+				User:            user,
+				Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+				HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+				Timeout:         timeout,
+			}
+			if client, err := ssh.Dial("tcp", addr, cfg); err == nil {
+				_ = client.Close()
+				return "weak-creds", redact(user, "") + " (publickey)"
+			}
+			jitterBackoff()
+		}
+		for _, pass := range opts.Passwords {
+			if attempts >= maxAttempts {
+				return "", ""
+			}
+			attempts++
+			opts.Limiter.Wait()
+			cfg := &ssh.ClientConfig{
+				User:            user,
+				Auth:            []ssh.AuthMethod{ssh.Password(pass)},
+				HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+				Timeout:         timeout,
+			}
+			if client, err := ssh.Dial("tcp", addr, cfg); err == nil {
+				_ = client.Close()
+				return "weak-creds", redact(user, pass)
+			}
+			jitterBackoff()
+		}
+	}
+	return "", ""
+}
+
+// LoadSSHKeys parses each of paths as a PEM-encoded private key, skipping
+// (and reporting) any that fail to parse rather than aborting the whole
+// load, the same tolerance LoadPOCs gives a directory of templates.
+func LoadSSHKeys(paths []string) ([]ssh.Signer, error) {
+	var signers []ssh.Signer
+	var errs []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		signer, err := ssh.ParsePrivateKey(data)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		signers = append(signers, signer)
+	}
+	if len(errs) > 0 {
+		return signers, fmt.Errorf("ssh key load errors: %s", strings.Join(errs, "; "))
+	}
+	return signers, nil
+}
@@ -0,0 +1,33 @@
+package authprobe
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// DefaultUsers and DefaultPasswords are short built-in lists used when the
+// caller doesn't supply --userlist/--passlist.
+var DefaultUsers = []string{"admin", "root", "user", "test", "guest"}
+var DefaultPasswords = []string{"admin", "password", "123456", "root", ""}
+
+// LoadWordlist reads one entry per line from path, skipping blank lines
+// and '#' comments.
+func LoadWordlist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		out = append(out, line)
+	}
+	return out, sc.Err()
+}
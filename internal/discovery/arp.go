@@ -0,0 +1,74 @@
+package discovery
+
+import (
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/mdlayher/arp"
+)
+
+// arpResolve sends an ARP request for ip and returns its MAC address.
+// It only works for hosts on a directly-attached subnet, so it first
+// finds a local interface whose address range contains ip; if none
+// does (ip is routed, or on a different VLAN), it reports !ok without
+// touching the network.
+func arpResolve(ip string, timeout time.Duration) (string, bool) {
+	target := net.ParseIP(ip).To4()
+	if target == nil {
+		return "", false
+	}
+
+	iface, ok := localInterfaceFor(target)
+	if !ok {
+		return "", false
+	}
+
+	client, err := arp.Dial(iface)
+	if err != nil {
+		return "", false
+	}
+	defer client.Close()
+
+	addr, ok := netip.AddrFromSlice(target)
+	if !ok {
+		return "", false
+	}
+
+	_ = client.SetDeadline(time.Now().Add(timeout))
+	mac, err := client.Resolve(addr)
+	if err != nil {
+		return "", false
+	}
+	return mac.String(), true
+}
+
+// localInterfaceFor returns the network interface with an IPv4 address
+// whose subnet contains ip, if any.
+func localInterfaceFor(ip net.IP) (*net.Interface, bool) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, false
+	}
+	for i := range ifaces {
+		iface := ifaces[i]
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			ipnet, ok := a.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			v4 := ipnet.IP.To4()
+			if v4 == nil {
+				continue
+			}
+			if ipnet.Contains(ip) {
+				return &iface, true
+			}
+		}
+	}
+	return nil, false
+}
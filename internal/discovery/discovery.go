@@ -0,0 +1,131 @@
+// Package discovery finds live hosts on a target range before the
+// scanner spends time port-scanning them. It runs three independent
+// sweeps — ICMP echo, ARP (for directly-attached subnets), and a NetBIOS
+// name query — and merges whatever each one turns up.
+package discovery
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HostInfo describes what the sweeps learned about one candidate IP.
+type HostInfo struct {
+	IP       string `json:"ip"`
+	Hostname string `json:"hostname,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	MAC      string `json:"mac,omitempty"`
+	Vendor   string `json:"vendor,omitempty"`
+	Platform string `json:"platform,omitempty"` // best-effort: "windows" or "samba"
+	Via      string `json:"via"`                // which sweep first found this host
+}
+
+// maxConcurrent bounds how many in-flight probes the sweeps run at once,
+// independent of how many candidates are supplied.
+const maxConcurrent = 256
+
+type probeResult struct {
+	ip       string
+	via      string
+	mac      string
+	hostname string
+	domain   string
+	platform string
+}
+
+// Sweep probes every candidate with ICMP, ARP, and NetBIOS in parallel
+// and returns only the hosts that answered at least one of them.
+func Sweep(candidates []string, timeout time.Duration) []HostInfo {
+	resultsCh := make(chan probeResult, len(candidates)*3)
+	sem := make(chan struct{}, maxConcurrent)
+	var wg sync.WaitGroup
+
+	run := func(ip string, fn func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fn()
+		}()
+	}
+
+	// ICMP shares a single raw socket across every candidate, so it's
+	// dispatched once rather than per-candidate.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		icmpSweep(candidates, timeout, func(ip string) {
+			resultsCh <- probeResult{ip: ip, via: "icmp"}
+		})
+	}()
+
+	for _, ip := range candidates {
+		ip := ip
+		run(ip, func() {
+			if mac, ok := arpResolve(ip, timeout); ok {
+				resultsCh <- probeResult{ip: ip, via: "arp", mac: mac}
+			}
+		})
+		run(ip, func() {
+			if info, ok := netbiosQuery(ip, timeout); ok {
+				resultsCh <- probeResult{
+					ip: ip, via: "netbios",
+					mac: info.mac, hostname: info.hostname,
+					domain: info.domain, platform: info.platform,
+				}
+			}
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultsCh)
+	}()
+
+	byIP := map[string]*HostInfo{}
+	for r := range resultsCh {
+		h, ok := byIP[r.ip]
+		if !ok {
+			h = &HostInfo{IP: r.ip, Via: r.via}
+			byIP[r.ip] = h
+		}
+		if r.mac != "" && h.MAC == "" {
+			h.MAC = r.mac
+			h.Vendor = lookupVendor(r.mac)
+		}
+		if r.hostname != "" {
+			h.Hostname = r.hostname
+		}
+		if r.domain != "" {
+			h.Domain = r.domain
+		}
+		if r.platform != "" {
+			h.Platform = r.platform
+		}
+	}
+
+	out := make([]HostInfo, 0, len(byIP))
+	for _, h := range byIP {
+		out = append(out, *h)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return ipLess(out[i].IP, out[j].IP)
+	})
+	return out
+}
+
+func ipLess(a, b string) bool {
+	ipA, ipB := net.ParseIP(a).To4(), net.ParseIP(b).To4()
+	if ipA == nil || ipB == nil {
+		return a < b
+	}
+	for i := 0; i < 4; i++ {
+		if ipA[i] != ipB[i] {
+			return ipA[i] < ipB[i]
+		}
+	}
+	return false
+}
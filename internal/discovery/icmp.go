@@ -0,0 +1,53 @@
+package discovery
+
+import (
+	"net"
+	"os"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+// icmpSweep sends one ICMP echo request to each candidate over a single
+// shared raw socket and calls found for every address that replies
+// before timeout. Opening the raw socket requires privilege; if it's
+// unavailable, icmpSweep is a no-op (ARP/NetBIOS may still find hosts).
+func icmpSweep(candidates []string, timeout time.Duration, found func(ip string)) {
+	conn, err := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	id := os.Getpid() & 0xffff
+	for i, ip := range candidates {
+		msg := icmp.Message{
+			Type: ipv4.ICMPTypeEcho,
+			Code: 0,
+			Body: &icmp.Echo{ID: id, Seq: i + 1, Data: []byte("portscanner-go discover")},
+		}
+		wb, err := msg.Marshal(nil)
+		if err != nil {
+			continue
+		}
+		_, _ = conn.WriteTo(wb, &net.IPAddr{IP: net.ParseIP(ip)})
+	}
+
+	deadline := time.Now().Add(timeout)
+	_ = conn.SetReadDeadline(deadline)
+	rb := make([]byte, 1500)
+	for {
+		n, peer, err := conn.ReadFrom(rb)
+		if err != nil {
+			return
+		}
+		reply, err := icmp.ParseMessage(1, rb[:n]) // protocol 1 = ICMP
+		if err != nil || reply.Type != ipv4.ICMPTypeEchoReply {
+			continue
+		}
+		if ipAddr, ok := peer.(*net.IPAddr); ok {
+			found(ipAddr.IP.String())
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package discovery
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	netbiosPort           = 137
+	netbiosStatTypeNBSTAT = 0x21
+	netbiosSuffixHostname = 0x00
+	netbiosSuffixDomain   = 0x1E // browser election group, used as a workgroup/domain stand-in
+	netbiosGroupFlag      = 0x8000
+)
+
+// netbiosInfo is what a single NBSTAT query can tell us about a host.
+type netbiosInfo struct {
+	hostname string
+	domain   string
+	mac      string
+	platform string // "windows" or "samba", best-effort
+}
+
+// netbiosQuery sends a NetBIOS Name Service NBSTAT request to ip:137 and
+// parses the name table in the reply. This is deliberately independent
+// from the scanner package's own NBSTAT probe (internal/scanner's is
+// scoped to port-scan fingerprinting, this one to host discovery) so
+// the two packages don't need to share internals.
+func netbiosQuery(ip string, timeout time.Duration) (netbiosInfo, bool) {
+	conn, err := net.DialTimeout("udp", net.JoinHostPort(ip, strconv.Itoa(netbiosPort)), timeout)
+	if err != nil {
+		return netbiosInfo{}, false
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write(netbiosEncodeStatusQuery()); err != nil {
+		return netbiosInfo{}, false
+	}
+
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return netbiosInfo{}, false
+	}
+	return parseNetBIOSStatus(buf[:n])
+}
+
+// netbiosEncodeStatusQuery builds an NBSTAT query for the wildcard name
+// "*", per RFC 1002's half-ASCII name encoding.
+func netbiosEncodeStatusQuery() []byte {
+	buf := make([]byte, 0, 50)
+	buf = append(buf, 0x00, 0x00) // TransactionID
+	buf = append(buf, 0x00, 0x00) // Flags: query
+	buf = append(buf, 0x00, 0x01) // QDCOUNT
+	buf = append(buf, 0x00, 0x00) // ANCOUNT
+	buf = append(buf, 0x00, 0x00) // NSCOUNT
+	buf = append(buf, 0x00, 0x00) // ARCOUNT
+
+	padded := make([]byte, 16)
+	copy(padded, "*")
+	for i := 1; i < 16; i++ {
+		padded[i] = ' '
+	}
+	buf = append(buf, 32) // length prefix: 32 encoded bytes follow
+	for _, b := range padded {
+		buf = append(buf, 'A'+(b>>4), 'A'+(b&0x0F))
+	}
+	buf = append(buf, 0) // name terminator
+
+	buf = append(buf, 0x00, netbiosStatTypeNBSTAT) // QTYPE: NBSTAT
+	buf = append(buf, 0x00, 0x01)                  // QCLASS: IN
+	return buf
+}
+
+// parseNetBIOSStatus walks the NBSTAT reply's name table, picking the
+// hostname (unique, suffix 0x00) and a domain/workgroup guess (suffix
+// 0x1E), then pulls the MAC out of the trailing statistics block. A
+// registered "__MSBROWSE__" group name is the standard signal that a
+// host runs the (Windows or Samba) browser service as a Windows-style
+// master browser; lacking a more specific check, we call that platform
+// "windows" and fall back to "samba" rather than leaving it blank.
+func parseNetBIOSStatus(msg []byte) (netbiosInfo, bool) {
+	const hdrLen = 12
+	pos := hdrLen + 34 + 4 + 2 + 8 + 2 // header + question name + QTYPE/QCLASS + answer name ptr + TYPE/CLASS/TTL + RDLENGTH
+	if pos >= len(msg) {
+		return netbiosInfo{}, false
+	}
+	numNames := int(msg[pos])
+	pos++
+
+	var info netbiosInfo
+	sawMSBrowse := false
+	for i := 0; i < numNames && pos+18 <= len(msg); i++ {
+		rawName := msg[pos : pos+15]
+		suffix := msg[pos+15]
+		flags := uint16(msg[pos+16])<<8 | uint16(msg[pos+17])
+		name := strings.TrimRight(string(rawName), " ")
+		isGroup := flags&netbiosGroupFlag != 0
+
+		switch {
+		case strings.Contains(name, "__MSBROWSE__"): // on the wire: "\x01\x02__MSBROWSE__\x02"
+			sawMSBrowse = true
+		case !isGroup && suffix == netbiosSuffixHostname && info.hostname == "":
+			info.hostname = name
+		case isGroup && suffix == netbiosSuffixDomain && info.domain == "":
+			info.domain = name
+		}
+		pos += 18
+	}
+
+	if sawMSBrowse {
+		info.platform = "windows"
+	} else if info.hostname != "" {
+		info.platform = "samba"
+	}
+
+	// Statistics block follows the name table: a 6-byte unit ID (MAC),
+	// then fields we don't need.
+	if pos+6 <= len(msg) {
+		mac := msg[pos : pos+6]
+		if mac[0]|mac[1]|mac[2]|mac[3]|mac[4]|mac[5] != 0 {
+			info.mac = net.HardwareAddr(mac).String()
+		}
+	}
+
+	if info.hostname == "" && info.mac == "" {
+		return netbiosInfo{}, false
+	}
+	return info, true
+}
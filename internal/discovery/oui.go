@@ -0,0 +1,63 @@
+package discovery
+
+import "strings"
+
+// ouiVendors maps the first three octets of a MAC address (upper-case,
+// colon-separated) to the registered vendor name. This is a small,
+// curated subset of the IEEE OUI database covering common consumer and
+// datacenter hardware — not a full copy, which would run to hundreds of
+// thousands of entries.
+var ouiVendors = map[string]string{
+	"00:1A:11": "Google",
+	"3C:5A:B4": "Google",
+	"F4:F5:D8": "Google",
+	"B8:27:EB": "Raspberry Pi Foundation",
+	"DC:A6:32": "Raspberry Pi Foundation",
+	"E4:5F:01": "Raspberry Pi Foundation",
+	"00:50:56": "VMware",
+	"00:0C:29": "VMware",
+	"00:05:69": "VMware",
+	"08:00:27": "Oracle VirtualBox",
+	"52:54:00": "QEMU/KVM",
+	"00:1C:42": "Parallels",
+	"00:16:3E": "Xen",
+	"00:15:5D": "Microsoft Hyper-V",
+	"00:50:F2": "Microsoft",
+	"00:1D:D8": "Microsoft",
+	"FC:FB:FB": "Cisco",
+	"00:1B:D4": "Cisco",
+	"00:0A:41": "Cisco",
+	"A4:56:30": "Ubiquiti Networks",
+	"24:A4:3C": "Ubiquiti Networks",
+	"F0:9F:C2": "Ubiquiti Networks",
+	"00:11:32": "Synology",
+	"00:1C:B3": "Apple",
+	"AC:DE:48": "Apple",
+	"F0:18:98": "Apple",
+	"00:17:88": "Philips Hue",
+	"B0:C5:54": "Tenda",
+	"18:E8:29": "Netgear",
+	"A0:40:A0": "Netgear",
+	"C0:3F:0E": "Netgear",
+	"00:14:BF": "D-Link",
+	"00:1E:58": "D-Link",
+	"EC:08:6B": "D-Link",
+	"00:18:4D": "TP-Link",
+	"50:C7:BF": "TP-Link",
+	"F4:F2:6D": "TP-Link",
+	"00:E0:4C": "Realtek",
+	"00:1F:33": "NETGEAR",
+	"DC:A9:71": "Ruckus Wireless",
+	"00:90:A9": "Western Digital",
+	"00:25:90": "Super Micro Computer",
+}
+
+// lookupVendor returns a best-effort vendor name for a MAC address based
+// on its OUI prefix, or "" if it isn't in the curated table.
+func lookupVendor(mac string) string {
+	if len(mac) < 8 {
+		return ""
+	}
+	prefix := strings.ToUpper(mac[:8])
+	return ouiVendors[prefix]
+}
@@ -35,6 +35,13 @@ func isTTY(w io.Writer) bool {
 	return term.IsTerminal(int(f.Fd()))
 }
 
+// IsTTY exports the same terminal check for callers outside this package
+// that need to decide whether to render in-place (e.g. a live status
+// block), not just colorize.
+func IsTTY(w io.Writer) bool {
+	return isTTY(w)
+}
+
 func colorize(enabled bool, color string, s string) string {
 	if !enabled {
 		return s
@@ -42,6 +49,27 @@ func colorize(enabled bool, color string, s string) string {
 	return color + s + ansiReset
 }
 
+// discoveryLine renders the hostname/MAC/vendor line shown above a
+// host's port table when a discovery sweep found that information;
+// it returns "" when none of those fields are set.
+func discoveryLine(r scanner.Result) string {
+	parts := []string{}
+	if r.Hostname != "" {
+		parts = append(parts, r.Hostname)
+	}
+	if r.MAC != "" {
+		mac := r.MAC
+		if r.Vendor != "" {
+			mac = fmt.Sprintf("%s (%s)", mac, r.Vendor)
+		}
+		parts = append(parts, mac)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "  " + strings.Join(parts, " - ")
+}
+
 func PrintTable(w io.Writer, results []scanner.Result) {
 	if len(results) == 0 {
 		fmt.Fprintln(w, "No results.")
@@ -60,6 +88,9 @@ func PrintTable(w io.Writer, results []scanner.Result) {
 	for _, h := range hosts {
 		// Host header
 		fmt.Fprintf(w, "%s%sHost:%s %s\n", ansiBold, ansiCyan, ansiReset, h)
+		if line := discoveryLine(byHost[h][0]); line != "" {
+			fmt.Fprintln(w, colorize(useColor, ansiDim, line))
+		}
 		// Table header
 		header := fmt.Sprintf("%-5s %-7s %-8s  %s", "PORT", "STATE", "LATENCY", "SERVICE")
 		fmt.Fprintln(w, colorize(useColor, ansiGray, header))
@@ -71,11 +102,16 @@ func PrintTable(w io.Writer, results []scanner.Result) {
 			if r.Open {
 				state = "open"
 			}
+			if r.Proto == "udp" && r.State != "" {
+				state = r.State
+			}
 			// Color state
 			stateStr := state
 			if useColor {
 				if r.Open {
 					stateStr = colorize(true, ansiGreen+ansiBold, state)
+				} else if state == "open|filtered" {
+					stateStr = colorize(true, ansiYellow, state)
 				} else {
 					stateStr = colorize(true, ansiRed, state)
 				}
@@ -116,6 +152,27 @@ func PrintTable(w io.Writer, results []scanner.Result) {
 			}
 			line := strings.Join(parts, " ")
 			fmt.Fprintf(w, "%-5d %-7s %8s  %s\n", r.Port, stateStr, latStr, line)
+			if len(r.Tech) > 0 {
+				tag := "[tech]"
+				if useColor {
+					tag = colorize(true, ansiMagenta+ansiBold, tag)
+				}
+				fmt.Fprintf(w, "      %s %s\n", tag, strings.Join(r.Tech, ", "))
+			}
+			if r.AuthState != "" {
+				tag := fmt.Sprintf("[auth:%s]", r.AuthState)
+				if useColor {
+					tag = colorize(true, ansiYellow+ansiBold, tag)
+				}
+				fmt.Fprintf(w, "      %s %s\n", tag, r.Credentials)
+			}
+			for _, v := range r.Vulns {
+				tag := fmt.Sprintf("[%s/%s]", v.ID, v.Severity)
+				if useColor {
+					tag = colorize(true, ansiRed+ansiBold, tag)
+				}
+				fmt.Fprintf(w, "      %s %s\n", tag, v.Description)
+			}
 		}
 		fmt.Fprintln(w)
 	}
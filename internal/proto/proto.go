@@ -0,0 +1,88 @@
+// Package proto defines the JSON-over-TCP wire format shared between a
+// controller (portscanner-go --controller ...) and the agents it
+// dispatches shards of work to (portscanner-go serve).
+package proto
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"portscanner-go/internal/scanner"
+)
+
+// Job is one host:port unit of work, the thing a controller shards out
+// and an agent scans.
+type Job struct {
+	Host  string `json:"host"`
+	Port  int    `json:"port"`
+	Proto string `json:"proto"` // "tcp" or "udp"
+}
+
+// ScanRequest is sent once per connection: the shard of jobs an agent
+// should run, plus the scan options to run them with.
+type ScanRequest struct {
+	Jobs    []Job         `json:"jobs"`
+	Timeout time.Duration `json:"timeout"`
+	Workers int           `json:"workers"`
+	Probe   bool          `json:"probe"`
+	Vuln    bool          `json:"vuln"`
+}
+
+// DoneStats summarizes an agent's contribution once it finishes a
+// ScanRequest. The controller's per-agent summary counts the streamed
+// Results itself and does not read these fields.
+type DoneStats struct {
+	JobsCompleted int   `json:"jobs_completed"`
+	OpenPorts     int   `json:"open_ports"`
+	ElapsedMS     int64 `json:"elapsed_ms"`
+}
+
+// MsgType discriminates the Envelope variants sent over the wire.
+type MsgType string
+
+const (
+	MsgScanRequest MsgType = "scan_request"
+	MsgResult      MsgType = "result"
+	MsgDone        MsgType = "done"
+)
+
+// Envelope wraps every message sent in either direction: a controller
+// sends a single MsgScanRequest, an agent streams back one MsgResult per
+// scanned job followed by a single MsgDone.
+type Envelope struct {
+	Type    MsgType         `json:"type"`
+	Request *ScanRequest    `json:"request,omitempty"`
+	Result  *scanner.Result `json:"result,omitempty"`
+	Stats   *DoneStats      `json:"stats,omitempty"`
+}
+
+// Writer streams Envelopes out over a connection as consecutive JSON
+// values; encoding/json.Decoder on the other end reads them back without
+// any additional framing.
+type Writer struct {
+	enc *json.Encoder
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{enc: json.NewEncoder(w)}
+}
+
+func (w *Writer) Send(e Envelope) error {
+	return w.enc.Encode(e)
+}
+
+// Reader reads back-to-back Envelopes from a connection.
+type Reader struct {
+	dec *json.Decoder
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{dec: json.NewDecoder(r)}
+}
+
+func (r *Reader) Recv() (Envelope, error) {
+	var e Envelope
+	err := r.dec.Decode(&e)
+	return e, err
+}
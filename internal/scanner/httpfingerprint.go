@@ -0,0 +1,166 @@
+package scanner
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"math/bits"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// insecureHTTPTransport matches tlsProbe's InsecureSkipVerify: true — the
+// scanner is talking to unknown hosts, so cert validation isn't the point.
+var insecureHTTPTransport = &http.Transport{
+	TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+}
+
+// maxHTTPBodyRead caps how much of a response body the fingerprinter will
+// read, so a probe against a misbehaving or huge endpoint can't stall a
+// worker or blow up memory.
+const maxHTTPBodyRead = 64 * 1024
+
+var titleRe = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// extractTitle pulls the page <title>, collapsed to a single line.
+func extractTitle(body string) string {
+	m := titleRe.FindStringSubmatch(body)
+	if m == nil {
+		return ""
+	}
+	return strings.TrimSpace(sanitizeBanner(m[1]))
+}
+
+// techSignature is one entry in the tech-detection catalog: a named
+// technology plus the header/body patterns that identify it. A
+// signature only needs one of headerRe/bodyRe set.
+type techSignature struct {
+	name     string
+	headerRe *regexp.Regexp
+	bodyRe   *regexp.Regexp
+}
+
+// techCatalog seeds detection for common, high-signal web apps and admin
+// consoles; it's intentionally small rather than an exhaustive Wappalyzer-
+// style database, since its only job here is gating which PoCs run.
+var techCatalog = []techSignature{
+	{name: "wordpress", bodyRe: regexp.MustCompile(`(?i)wp-content|wp-includes|/wp-json/`)},
+	{name: "jenkins", headerRe: regexp.MustCompile(`(?im)^X-Jenkins:`), bodyRe: regexp.MustCompile(`(?i)Jenkins`)},
+	{name: "gitlab", bodyRe: regexp.MustCompile(`(?i)gitlab|data-page="projects`)},
+	{name: "grafana", bodyRe: regexp.MustCompile(`(?i)<title>\s*Grafana|content="Grafana"`)},
+	{name: "kibana", bodyRe: regexp.MustCompile(`(?i)<title>\s*Kibana|kbn-injected-metadata`)},
+	{name: "phpmyadmin", bodyRe: regexp.MustCompile(`(?i)phpMyAdmin`)},
+	{name: "tomcat-manager", bodyRe: regexp.MustCompile(`(?i)Tomcat Web Application Manager`)},
+	{name: "spring-boot-actuator", bodyRe: regexp.MustCompile(`(?i)"_links"\s*:\s*{\s*"self"|org\.springframework\.boot`)},
+	{name: "weblogic", bodyRe: regexp.MustCompile(`(?i)WebLogic Server|console/login/LoginForm`)},
+	{name: "struts", headerRe: regexp.MustCompile(`(?im)^X-Powered-By:.*struts`), bodyRe: regexp.MustCompile(`(?i)struts2?/|action="[^"]*\.action"`)},
+}
+
+// detectTech matches headerBlock (raw "Name: value\n..." header text) and
+// body against techCatalog and returns every technology that matched.
+func detectTech(headerBlock, body string) []string {
+	var found []string
+	for _, sig := range techCatalog {
+		if sig.headerRe != nil && sig.headerRe.MatchString(headerBlock) {
+			found = append(found, sig.name)
+			continue
+		}
+		if sig.bodyRe != nil && sig.bodyRe.MatchString(body) {
+			found = append(found, sig.name)
+		}
+	}
+	return found
+}
+
+// fetchFavicon retrieves /favicon.ico over its own short-lived HTTP(S)
+// client connection, separate from the raw fingerprint socket, since a
+// second request needs its own request/response cycle anyway.
+func fetchFavicon(host string, port int, useTLS bool, timeout time.Duration) ([]byte, bool) {
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+	url := scheme + "://" + net.JoinHostPort(host, strconv.Itoa(port)) + "/favicon.ico"
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: insecureHTTPTransport,
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, false
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPBodyRead))
+	if err != nil || len(data) == 0 {
+		return nil, false
+	}
+	return data, true
+}
+
+// faviconHash reproduces the mmh3-of-base64 convention popularized by
+// Shodan/ZoomEye for favicon fingerprinting: MIME-wrap the icon's base64
+// encoding at 76 columns (matching Python's base64.encodestring), then
+// take the signed 32-bit MurmurHash3 of that text.
+func faviconHash(data []byte) int32 {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var wrapped strings.Builder
+	for i := 0; i < len(encoded); i += 76 {
+		end := i + 76
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		wrapped.WriteString(encoded[i:end])
+		wrapped.WriteByte('\n')
+	}
+	return int32(murmurHash32([]byte(wrapped.String()), 0))
+}
+
+// murmurHash32 is the standard 32-bit x86 MurmurHash3 algorithm.
+func murmurHash32(data []byte, seed uint32) uint32 {
+	const c1 = 0xcc9e2d51
+	const c2 = 0x1b873593
+	h1 := seed
+	nblocks := len(data) / 4
+
+	for i := 0; i < nblocks; i++ {
+		k1 := uint32(data[i*4]) | uint32(data[i*4+1])<<8 | uint32(data[i*4+2])<<16 | uint32(data[i*4+3])<<24
+		k1 *= c1
+		k1 = bits.RotateLeft32(k1, 15)
+		k1 *= c2
+		h1 ^= k1
+		h1 = bits.RotateLeft32(h1, 13)
+		h1 = h1*5 + 0xe6546b64
+	}
+
+	tail := data[nblocks*4:]
+	var k1 uint32
+	switch len(tail) {
+	case 3:
+		k1 ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k1 ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k1 ^= uint32(tail[0])
+		k1 *= c1
+		k1 = bits.RotateLeft32(k1, 15)
+		k1 *= c2
+		h1 ^= k1
+	}
+
+	h1 ^= uint32(len(data))
+	h1 ^= h1 >> 16
+	h1 *= 0x85ebca6b
+	h1 ^= h1 >> 13
+	h1 *= 0xc2b2ae35
+	h1 ^= h1 >> 16
+	return h1
+}
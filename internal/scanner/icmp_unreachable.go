@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"net"
+	"sync"
+)
+
+// icmpUnreachableListener listens for ICMP destination-unreachable /
+// port-unreachable messages and lets UDP probes wait for one matching a
+// specific port. Opening a raw ICMP socket requires privilege; when that
+// fails, newICMPUnreachableListener returns nil and every method below is
+// a safe no-op, so callers can use it unconditionally.
+type icmpUnreachableListener struct {
+	conn    net.PacketConn
+	hostIP  net.IP
+	mu      sync.Mutex
+	waiters map[int]chan struct{}
+}
+
+func newICMPUnreachableListener(targetHost string) *icmpUnreachableListener {
+	conn, err := net.ListenPacket("ip4:icmp", "0.0.0.0")
+	if err != nil {
+		return nil
+	}
+	var ip net.IP
+	if addr, err := net.ResolveIPAddr("ip4", targetHost); err == nil {
+		ip = addr.IP
+	}
+	l := &icmpUnreachableListener{conn: conn, hostIP: ip, waiters: map[int]chan struct{}{}}
+	go l.loop()
+	return l
+}
+
+func (l *icmpUnreachableListener) loop() {
+	buf := make([]byte, 2048)
+	for {
+		n, from, err := l.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if l.hostIP != nil {
+			if udpAddr, ok := from.(*net.IPAddr); ok && !udpAddr.IP.Equal(l.hostIP) {
+				continue
+			}
+		}
+		port, ok := parseICMPPortUnreachable(buf[:n])
+		if !ok {
+			continue
+		}
+		l.mu.Lock()
+		if ch, ok := l.waiters[port]; ok {
+			close(ch)
+			delete(l.waiters, port)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// Wait returns a channel that closes when a port-unreachable message for
+// port arrives. On a nil listener it returns a channel that never fires.
+func (l *icmpUnreachableListener) Wait(port int) <-chan struct{} {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if ch, ok := l.waiters[port]; ok {
+		return ch
+	}
+	ch := make(chan struct{})
+	l.waiters[port] = ch
+	return ch
+}
+
+func (l *icmpUnreachableListener) Close() {
+	if l == nil {
+		return
+	}
+	_ = l.conn.Close()
+}
+
+// parseICMPPortUnreachable reports the original destination port carried
+// inside an ICMP type 3 (destination unreachable), code 3 (port
+// unreachable) message.
+func parseICMPPortUnreachable(b []byte) (int, bool) {
+	if len(b) < 8 || b[0] != 3 || b[1] != 3 {
+		return 0, false
+	}
+	orig := b[8:]
+	if len(orig) < 20 {
+		return 0, false
+	}
+	ihl := int(orig[0]&0x0F) * 4
+	if ihl < 20 || len(orig) < ihl+4 {
+		return 0, false
+	}
+	udp := orig[ihl:]
+	return int(udp[2])<<8 | int(udp[3]), true
+}
@@ -0,0 +1,274 @@
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"portscanner-go/internal/authprobe"
+
+	"gopkg.in/yaml.v3"
+)
+
+// POC is a single YAML-declared proof-of-concept check, modeled on the
+// template format used by common web-scan engines: a handful of HTTP
+// request rules plus a boolean expression over the final response.
+type POC struct {
+	ID         string            `yaml:"id"`
+	Info       POCInfo           `yaml:"info"`
+	Tech       []string          `yaml:"tech"` // only runs against hosts fingerprinted with one of these; empty means always
+	Set        map[string]string `yaml:"set"`  // variable name -> generator expression, e.g. "randstr(8)"
+	Rules      []POCRule         `yaml:"rules"`
+	Expression string            `yaml:"expression"`
+}
+
+// POCInfo is the human-facing metadata nuclei-style templates keep under
+// an "info" block.
+type POCInfo struct {
+	Name     string `yaml:"name"`
+	Severity string `yaml:"severity"`
+}
+
+// POCRule is one HTTP request in a POC's chain. Path/Headers/Body may
+// reference {{varname}} placeholders filled in from the POC's Set block.
+type POCRule struct {
+	Method  string            `yaml:"method"`
+	Path    string            `yaml:"path"`
+	Headers map[string]string `yaml:"headers"`
+	Body    string            `yaml:"body"`
+}
+
+// LoadPOCs reads every *.yaml/*.yml file in dir and parses it as a POC.
+// A file that fails to parse doesn't abort the load; it's reported in
+// the returned error alongside whatever POCs did parse successfully.
+func LoadPOCs(dir string) ([]*POC, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read pocs dir: %w", err)
+	}
+	var pocs []*POC
+	var errs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		var p POC
+		if err := yaml.Unmarshal(data, &p); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		pocs = append(pocs, &p)
+	}
+	if len(errs) > 0 {
+		return pocs, fmt.Errorf("poc load errors: %s", strings.Join(errs, "; "))
+	}
+	return pocs, nil
+}
+
+// pocLimiters caps PoC requests per second per host, so scanning many
+// hosts concurrently doesn't divide one shared budget between them the
+// way authprobe.Options.Limiter does for credential attempts scoped to a
+// single service check.
+var (
+	pocLimitersMu sync.Mutex
+	pocLimiters   = map[string]*authprobe.RateLimiter{}
+)
+
+func pocLimiterFor(host string) *authprobe.RateLimiter {
+	pocLimitersMu.Lock()
+	defer pocLimitersMu.Unlock()
+	l, ok := pocLimiters[host]
+	if !ok {
+		l = authprobe.NewRateLimiter(5, time.Second)
+		pocLimiters[host] = l
+	}
+	return l
+}
+
+// RunPOCs executes every POC in pocs whose Tech list intersects detected
+// against host:port, returning a Vuln for each one whose expression
+// matches. Requests are unauthenticated and read-only by convention of
+// the POC author; RunPOCs itself only ever issues the requests a POC
+// file declares.
+func RunPOCs(pocs []*POC, host string, port int, service string, detected []string, timeout time.Duration) []Vuln {
+	if len(pocs) == 0 {
+		return nil
+	}
+	scheme := "http"
+	if service == "https" {
+		scheme = "https"
+	}
+	base := fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, strconv.Itoa(port)))
+	client := &http.Client{Timeout: timeout, Transport: insecureHTTPTransport}
+
+	var vulns []Vuln
+	for _, p := range pocs {
+		if !techMatches(p.Tech, detected) {
+			continue
+		}
+		if v := runOnePOC(client, base, host, p); v != nil {
+			vulns = append(vulns, *v)
+		}
+	}
+	return vulns
+}
+
+func techMatches(want, have []string) bool {
+	if len(want) == 0 {
+		return true
+	}
+	for _, w := range want {
+		for _, h := range have {
+			if strings.EqualFold(w, h) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func runOnePOC(client *http.Client, base, host string, p *POC) *Vuln {
+	limiter := pocLimiterFor(host)
+	vars := evalSet(p.Set)
+	var lastResp *pocResponse
+	for _, rule := range p.Rules {
+		limiter.Wait()
+		resp, err := execRule(client, base, rule, vars)
+		if err != nil {
+			return nil
+		}
+		lastResp = resp
+	}
+	if lastResp == nil {
+		return nil
+	}
+	matched, err := evalExpression(p.Expression, lastResp)
+	if err != nil || !matched {
+		return nil
+	}
+	return &Vuln{
+		ID:          p.ID,
+		Severity:    p.Info.Severity,
+		Description: p.Info.Name,
+		Evidence:    fmt.Sprintf("HTTP %d", lastResp.Status),
+	}
+}
+
+// pocResponse is the subset of an HTTP response the expression DSL can
+// reference.
+type pocResponse struct {
+	Status      int
+	Body        string
+	Headers     string
+	ContentType string
+}
+
+func execRule(client *http.Client, base string, rule POCRule, vars map[string]string) (*pocResponse, error) {
+	method := rule.Method
+	if method == "" {
+		method = "GET"
+	}
+	path := applyVars(rule.Path, vars)
+	body := applyVars(rule.Body, vars)
+
+	req, err := http.NewRequest(method, base+path, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range rule.Headers {
+		req.Header.Set(k, applyVars(v, vars))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxHTTPBodyRead))
+
+	var headerBlock strings.Builder
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			headerBlock.WriteString(k)
+			headerBlock.WriteString(": ")
+			headerBlock.WriteString(v)
+			headerBlock.WriteByte('\n')
+		}
+	}
+
+	return &pocResponse{
+		Status:      resp.StatusCode,
+		Body:        string(data),
+		Headers:     headerBlock.String(),
+		ContentType: resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+// applyVars substitutes {{name}} placeholders in s with vars[name].
+func applyVars(s string, vars map[string]string) string {
+	for k, v := range vars {
+		s = strings.ReplaceAll(s, "{{"+k+"}}", v)
+	}
+	return s
+}
+
+// evalSet computes the Set block's generator expressions once per POC
+// run. Supported generators: randstr(n) for a random alphanumeric
+// string, and revnum(n) for the decimal digits of n written backwards
+// (useful for cache-busting query params that still need to look
+// numeric).
+func evalSet(set map[string]string) map[string]string {
+	vars := make(map[string]string, len(set))
+	for name, expr := range set {
+		vars[name] = evalGenerator(expr)
+	}
+	return vars
+}
+
+const randstrAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func evalGenerator(expr string) string {
+	expr = strings.TrimSpace(expr)
+	switch {
+	case strings.HasPrefix(expr, "randstr(") && strings.HasSuffix(expr, ")"):
+		n, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(expr, "randstr("), ")"))
+		if n <= 0 {
+			n = 8
+		}
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = randstrAlphabet[rand.Intn(len(randstrAlphabet))]
+		}
+		return string(b)
+	case strings.HasPrefix(expr, "revnum(") && strings.HasSuffix(expr, ")"):
+		n, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(expr, "revnum("), ")"))
+		b := []byte(strconv.Itoa(n))
+		for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+			b[i], b[j] = b[j], b[i]
+		}
+		return string(b)
+	default:
+		return expr
+	}
+}
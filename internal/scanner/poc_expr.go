@@ -0,0 +1,171 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// evalExpression evaluates a POC's boolean expression DSL against resp.
+// Supported grammar:
+//
+//	expr       := and ('||' and)*
+//	and        := primary ('&&' primary)*
+//	primary    := '(' expr ')' | call | comparison
+//	call       := ('contains' | 'bmatch') '(' field ',' string ')'
+//	comparison := field ('==' | '!=') (string | number)
+//	field      := response.status | response.body | response.headers | response.content_type
+var exprTokenRe = regexp.MustCompile(`"[^"]*"|&&|\|\||==|!=|\(|\)|,|[A-Za-z0-9_.]+`)
+
+func evalExpression(expr string, resp *pocResponse) (bool, error) {
+	p := &exprParser{tokens: exprTokenRe.FindAllString(expr, -1)}
+	v, err := p.parseOr(resp)
+	if err != nil {
+		return false, err
+	}
+	if p.pos != len(p.tokens) {
+		return false, fmt.Errorf("unexpected trailing input in expression %q", expr)
+	}
+	return v, nil
+}
+
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *exprParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *exprParser) parseOr(resp *pocResponse) (bool, error) {
+	left, err := p.parseAnd(resp)
+	if err != nil {
+		return false, err
+	}
+	for p.peek() == "||" {
+		p.next()
+		right, err := p.parseAnd(resp)
+		if err != nil {
+			return false, err
+		}
+		left = left || right
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd(resp *pocResponse) (bool, error) {
+	left, err := p.parsePrimary(resp)
+	if err != nil {
+		return false, err
+	}
+	for p.peek() == "&&" {
+		p.next()
+		right, err := p.parsePrimary(resp)
+		if err != nil {
+			return false, err
+		}
+		left = left && right
+	}
+	return left, nil
+}
+
+func (p *exprParser) parsePrimary(resp *pocResponse) (bool, error) {
+	switch p.peek() {
+	case "(":
+		p.next()
+		v, err := p.parseOr(resp)
+		if err != nil {
+			return false, err
+		}
+		if p.next() != ")" {
+			return false, fmt.Errorf("expected )")
+		}
+		return v, nil
+	case "contains", "bmatch":
+		return p.parseCall(resp)
+	default:
+		return p.parseComparison(resp)
+	}
+}
+
+func (p *exprParser) parseCall(resp *pocResponse) (bool, error) {
+	fn := p.next()
+	if p.next() != "(" {
+		return false, fmt.Errorf("expected ( after %s", fn)
+	}
+	field := p.next()
+	if p.next() != "," {
+		return false, fmt.Errorf("expected , in %s(...)", fn)
+	}
+	arg := p.next()
+	if p.next() != ")" {
+		return false, fmt.Errorf("expected ) after %s(...)", fn)
+	}
+	value, err := fieldValue(field, resp)
+	if err != nil {
+		return false, err
+	}
+	needle := unquote(arg)
+	switch fn {
+	case "contains":
+		return strings.Contains(value, needle), nil
+	case "bmatch":
+		re, err := regexp.Compile(needle)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(value), nil
+	}
+	return false, fmt.Errorf("unknown function %q", fn)
+}
+
+func (p *exprParser) parseComparison(resp *pocResponse) (bool, error) {
+	field := p.next()
+	op := p.next()
+	rhs := p.next()
+	if op != "==" && op != "!=" {
+		return false, fmt.Errorf("expected == or != after %q, got %q", field, op)
+	}
+	value, err := fieldValue(field, resp)
+	if err != nil {
+		return false, err
+	}
+	rhsVal := unquote(rhs)
+	if op == "==" {
+		return value == rhsVal, nil
+	}
+	return value != rhsVal, nil
+}
+
+func fieldValue(field string, resp *pocResponse) (string, error) {
+	switch field {
+	case "response.status":
+		return strconv.Itoa(resp.Status), nil
+	case "response.body":
+		return resp.Body, nil
+	case "response.headers":
+		return resp.Headers, nil
+	case "response.content_type":
+		return resp.ContentType, nil
+	default:
+		return "", fmt.Errorf("unknown field %q", field)
+	}
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,60 @@
+package scanner
+
+import "testing"
+
+func TestEvalExpression(t *testing.T) {
+	resp := &pocResponse{
+		Status:      200,
+		Body:        "<title>Welcome to phpMyAdmin</title>",
+		Headers:     "Server: Apache\nX-Powered-By: PHP/7.4\n",
+		ContentType: "text/html; charset=UTF-8",
+	}
+
+	cases := []struct {
+		name string
+		expr string
+		want bool
+	}{
+		{"contains match", `contains(response.body, "phpMyAdmin")`, true},
+		{"contains no match", `contains(response.body, "Grafana")`, false},
+		{"bmatch match", `bmatch(response.headers, "X-Powered-By: PHP/.*")`, true},
+		{"bmatch no match", `bmatch(response.headers, "X-Powered-By: Go/.*")`, false},
+		{"status equals", `response.status == "200"`, true},
+		{"status not equals", `response.status != "200"`, false},
+		{"content type not equals", `response.content_type != "application/json"`, true},
+		{"and both true", `contains(response.body, "phpMyAdmin") && response.status == "200"`, true},
+		{"and one false", `contains(response.body, "phpMyAdmin") && response.status == "404"`, false},
+		{"or one true", `contains(response.body, "Grafana") || response.status == "200"`, true},
+		{"or both false", `contains(response.body, "Grafana") || response.status == "404"`, false},
+		{"parens", `(contains(response.body, "Grafana") || response.status == "200") && response.status != "500"`, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := evalExpression(tc.expr, resp)
+			if err != nil {
+				t.Fatalf("evalExpression(%q): unexpected error: %v", tc.expr, err)
+			}
+			if got != tc.want {
+				t.Errorf("evalExpression(%q) = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEvalExpressionErrors(t *testing.T) {
+	resp := &pocResponse{Status: 200}
+
+	cases := []string{
+		`response.nonexistent == "x"`,
+		`response.status ~= "200"`,
+		`contains(response.body, "x"`,
+		`response.status == "200" &&`,
+	}
+
+	for _, expr := range cases {
+		if _, err := evalExpression(expr, resp); err == nil {
+			t.Errorf("evalExpression(%q): expected an error, got none", expr)
+		}
+	}
+}
@@ -4,26 +4,49 @@ import (
 	"bufio"
 	"crypto/tls"
 	"fmt"
+	"io"
 	"net"
 	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"portscanner-go/internal/authprobe"
 )
 
 type Result struct {
 	Host        string        `json:"host"`
 	Port        int           `json:"port"`
+	Proto       string        `json:"proto,omitempty"` // "udp" for UDP scans; empty means tcp
 	Open        bool          `json:"open"`
+	State       string        `json:"state,omitempty"` // UDP only: open, open|filtered, closed
 	Latency     time.Duration `json:"latency_ms"`
 	Service     string        `json:"service,omitempty"`
 	Banner      string        `json:"banner,omitempty"`
 	Fingerprint string        `json:"fingerprint,omitempty"`
+	Vulns       []Vuln        `json:"vulns,omitempty"`
+	AuthState   string        `json:"auth_state,omitempty"`
+	Credentials string        `json:"credentials,omitempty"`
+	Tech        []string      `json:"tech,omitempty"`
 	Err         string        `json:"error,omitempty"`
+
+	// Hostname, MAC, and Vendor are populated from a prior internal/discovery
+	// sweep, if one ran; they're repeated on every row for the host, the
+	// same way Host itself is, so JSON output doesn't need a separate
+	// per-host section.
+	Hostname string `json:"hostname,omitempty"`
+	MAC      string `json:"mac,omitempty"`
+	Vendor   string `json:"vendor,omitempty"`
 }
 
-// ScanHostPorts performs a TCP connect scan with a worker pool.
-func ScanHostPorts(host string, ports []int, timeout time.Duration, workers int, probe bool) []Result {
+// ScanHostPorts performs a TCP connect scan with a worker pool. When probe
+// is set, open ports are fingerprinted; when vuln is set (implies probe),
+// identified services are additionally run through the registered
+// VulnProbes for their service name. When auth is non-nil, identified
+// services are passed to internal/authprobe for a capped, rate-limited
+// credential check. When pocs is non-empty, HTTP(S) ports additionally run
+// every POC whose tech list matches what was fingerprinted.
+func ScanHostPorts(host string, ports []int, timeout time.Duration, workers int, probe bool, vuln bool, auth *authprobe.Options, pocs []*POC) []Result {
 	if workers <= 0 {
 		workers = 100
 	}
@@ -42,15 +65,27 @@ func ScanHostPorts(host string, ports []int, timeout time.Duration, workers int,
 				lat := time.Since(start)
 				if err == nil {
 					service, banner, fp := "", "", ""
+					var tech []string
 					if probe {
-						service, banner, fp = fingerprintService(host, p, conn, timeout)
+						service, banner, fp, tech = fingerprintService(host, p, conn, timeout)
 					}
 					// Fallback to known service name if detection didn't yield one
 					if service == "" {
 						service = knownServiceForPort(p)
 					}
 					_ = conn.Close()
-					results <- Result{Host: host, Port: p, Open: true, Latency: lat, Service: service, Banner: banner, Fingerprint: fp}
+					var vulns []Vuln
+					if vuln && service != "" {
+						vulns = runVulnProbes(service, host, p, timeout)
+					}
+					if service == "http" || service == "https" {
+						vulns = append(vulns, RunPOCs(pocs, host, p, service, tech, timeout)...)
+					}
+					authState, creds := "", ""
+					if auth != nil && service != "" {
+						authState, creds = authprobe.Check(service, host, p, timeout, auth)
+					}
+					results <- Result{Host: host, Port: p, Open: true, Latency: lat, Service: service, Banner: banner, Fingerprint: fp, Vulns: vulns, AuthState: authState, Credentials: creds, Tech: tech}
 				} else {
 					results <- Result{Host: host, Port: p, Open: false, Latency: lat, Err: err.Error()}
 				}
@@ -101,7 +136,7 @@ func sanitizeBanner(s string) string {
 	return string(res)
 }
 
-func fingerprintService(host string, port int, conn net.Conn, timeout time.Duration) (string, string, string) {
+func fingerprintService(host string, port int, conn net.Conn, timeout time.Duration) (string, string, string, []string) {
 	// Try to read any immediate banner
 	_ = conn.SetReadDeadline(time.Now().Add(timeout / 3))
 	buf := make([]byte, 256)
@@ -122,60 +157,60 @@ func fingerprintService(host string, port int, conn net.Conn, timeout time.Durat
 			}
 		}
 		if banner != "" {
-			return "ssh", banner, banner
+			return "ssh", banner, banner, nil
 		}
-		return "ssh", "", ""
+		return "ssh", "", "", nil
 	}
 
 	// SMTP/ESMTP
 	if port == 25 || port == 587 || port == 465 {
 		if strings.Contains(strings.ToUpper(banner), "SMTP") || strings.HasPrefix(banner, "220 ") {
-			return "smtp", banner, banner
+			return "smtp", banner, banner, nil
 		}
 	}
 
 	// FTP
 	if port == 21 {
 		if strings.HasPrefix(banner, "220 ") || strings.Contains(strings.ToUpper(banner), "FTP") {
-			return "ftp", banner, banner
+			return "ftp", banner, banner, nil
 		}
 	}
 
 	// POP3
 	if port == 110 {
 		if strings.HasPrefix(banner, "+OK") || strings.Contains(strings.ToUpper(banner), "POP3") {
-			return "pop3", banner, banner
+			return "pop3", banner, banner, nil
 		}
 	}
 
 	// IMAP
 	if port == 143 {
 		if strings.HasPrefix(banner, "* OK") || strings.Contains(strings.ToUpper(banner), "IMAP") {
-			return "imap", banner, banner
+			return "imap", banner, banner, nil
 		}
 	}
 
 	// Try HTTP probe on common HTTP ports
 	if isHTTPPort(port) {
-		svc, b, fp := httpProbe(host, conn, timeout)
+		svc, b, fp, tech := httpProbe(host, port, conn, timeout)
 		if svc != "" {
-			return svc, b, fp
+			return svc, b, fp, tech
 		}
 	}
 
 	// Try TLS handshake on common TLS ports
 	if isTLSPort(port) {
-		svc, b, fp := tlsProbe(host, conn, timeout, port)
+		svc, b, fp, tech := tlsProbe(host, conn, timeout, port)
 		if svc != "" {
-			return svc, b, fp
+			return svc, b, fp, tech
 		}
 	}
 
 	// Fallback: just return any banner we saw
 	if banner != "" {
-		return "", banner, ""
+		return "", banner, "", nil
 	}
-	return "", "", ""
+	return "", "", "", nil
 }
 
 func isHTTPPort(p int) bool {
@@ -194,7 +229,7 @@ func isTLSPort(p int) bool {
 	return false
 }
 
-func httpProbe(host string, conn net.Conn, timeout time.Duration) (string, string, string) {
+func httpProbe(host string, port int, conn net.Conn, timeout time.Duration) (string, string, string, []string) {
 	_ = conn.SetWriteDeadline(time.Now().Add(timeout / 2))
 	_ = conn.SetReadDeadline(time.Now().Add(timeout))
 	req := fmt.Sprintf("GET / HTTP/1.0\r\nHost: %s\r\nUser-Agent: portscanner-go\r\nConnection: close\r\n\r\n", host)
@@ -203,9 +238,9 @@ func httpProbe(host string, conn net.Conn, timeout time.Duration) (string, strin
 	line, _ := r.ReadString('\n')
 	line = sanitizeBanner(line)
 	if strings.HasPrefix(line, "HTTP/") {
-		// Collect Server header if present
+		var headerBlock strings.Builder
 		server := ""
-		for i := 0; i < 20; i++ { // read limited headers
+		for i := 0; i < 40; i++ { // read limited headers
 			h, err := r.ReadString('\n')
 			if err != nil {
 				break
@@ -214,22 +249,42 @@ func httpProbe(host string, conn net.Conn, timeout time.Duration) (string, strin
 			if hs == "" { // end of headers
 				break
 			}
+			headerBlock.WriteString(hs)
+			headerBlock.WriteByte('\n')
 			if strings.HasPrefix(strings.ToLower(hs), "server:") {
 				server = sanitizeBanner(strings.TrimSpace(hs[7:]))
 			}
 		}
-		fp := strings.TrimSpace(strings.Join([]string{line, server}, " "))
-		return "http", line, fp
+		body, _ := io.ReadAll(io.LimitReader(r, maxHTTPBodyRead))
+		bodyStr := string(body)
+		title := extractTitle(bodyStr)
+		tech := detectTech(headerBlock.String(), bodyStr)
+
+		fpParts := []string{line}
+		if server != "" {
+			fpParts = append(fpParts, "Server="+server)
+		}
+		if title != "" {
+			fpParts = append(fpParts, fmt.Sprintf("Title=%q", title))
+		}
+		if icon, ok := fetchFavicon(host, port, false, timeout); ok {
+			fpParts = append(fpParts, fmt.Sprintf("Favicon=%d", faviconHash(icon)))
+		}
+		if len(tech) > 0 {
+			fpParts = append(fpParts, "Tech="+strings.Join(tech, ","))
+		}
+		fp := strings.TrimSpace(strings.Join(fpParts, " "))
+		return "http", line, fp, tech
 	}
-	return "", "", ""
+	return "", "", "", nil
 }
 
-func tlsProbe(host string, baseConn net.Conn, timeout time.Duration, port int) (string, string, string) {
+func tlsProbe(host string, baseConn net.Conn, timeout time.Duration, port int) (string, string, string, []string) {
 	cfg := &tls.Config{InsecureSkipVerify: true, ServerName: host}
 	tlsConn := tls.Client(baseConn, cfg)
 	_ = tlsConn.SetDeadline(time.Now().Add(timeout))
 	if err := tlsConn.Handshake(); err != nil {
-		return "", "", ""
+		return "", "", "", nil
 	}
 	cs := tlsConn.ConnectionState()
 	svc := "tls"
@@ -259,18 +314,20 @@ func tlsProbe(host string, baseConn net.Conn, timeout time.Duration, port int) (
 		fpParts = append(fpParts, fmt.Sprintf("ALPN=%s", cs.NegotiatedProtocol))
 	}
 	fp := strings.Join(fpParts, ", ")
-	// Optional: tiny HTTP HEAD to get server header on HTTPS
+	// On HTTPS, GET (not HEAD) so we get a body to pull the title and
+	// detect tech from, same as the plaintext httpProbe.
+	var tech []string
 	if svc == "https" {
 		_ = tlsConn.SetWriteDeadline(time.Now().Add(timeout / 2))
 		_ = tlsConn.SetReadDeadline(time.Now().Add(timeout))
-		_, _ = tlsConn.Write([]byte(fmt.Sprintf("HEAD / HTTP/1.0\r\nHost: %s\r\nConnection: close\r\n\r\n", host)))
+		_, _ = tlsConn.Write([]byte(fmt.Sprintf("GET / HTTP/1.0\r\nHost: %s\r\nUser-Agent: portscanner-go\r\nConnection: close\r\n\r\n", host)))
 		rd := bufio.NewReader(tlsConn)
 		line, _ := rd.ReadString('\n')
 		line = sanitizeBanner(line)
 		if strings.HasPrefix(line, "HTTP/") {
-			// Scan headers briefly for Server
+			var headerBlock strings.Builder
 			server := ""
-			for i := 0; i < 20; i++ {
+			for i := 0; i < 40; i++ {
 				h, err := rd.ReadString('\n')
 				if err != nil {
 					break
@@ -279,20 +336,45 @@ func tlsProbe(host string, baseConn net.Conn, timeout time.Duration, port int) (
 				if hs == "" {
 					break
 				}
+				headerBlock.WriteString(hs)
+				headerBlock.WriteByte('\n')
 				if strings.HasPrefix(strings.ToLower(hs), "server:") {
 					server = sanitizeBanner(strings.TrimSpace(hs[7:]))
 				}
 			}
+			body, _ := io.ReadAll(io.LimitReader(rd, maxHTTPBodyRead))
+			bodyStr := string(body)
+			title := extractTitle(bodyStr)
+			tech = detectTech(headerBlock.String(), bodyStr)
+
 			if server != "" {
 				if fp != "" {
 					fp += ", "
 				}
 				fp += "Server=" + server
 			}
-			return svc, line, fp
+			if title != "" {
+				if fp != "" {
+					fp += ", "
+				}
+				fp += fmt.Sprintf("Title=%q", title)
+			}
+			if icon, ok := fetchFavicon(host, port, true, timeout); ok {
+				if fp != "" {
+					fp += ", "
+				}
+				fp += fmt.Sprintf("Favicon=%d", faviconHash(icon))
+			}
+			if len(tech) > 0 {
+				if fp != "" {
+					fp += ", "
+				}
+				fp += "Tech=" + strings.Join(tech, ",")
+			}
+			return svc, line, fp, tech
 		}
 	}
-	return svc, "", fp
+	return svc, "", fp, tech
 }
 
 // knownServiceForPort returns a best-effort service name for common ports.
@@ -334,6 +416,8 @@ func knownServiceForPort(p int) string {
 		return "ntp"
 	case 135:
 		return "msrpc"
+	case 137:
+		return "netbios-ns"
 	case 139:
 		return "netbios-ssn"
 	case 143:
@@ -400,6 +484,10 @@ func knownServiceForPort(p int) string {
 		return "mongodb"
 	case 11211:
 		return "memcached"
+	case 1900:
+		return "ssdp"
+	case 5353:
+		return "mdns"
 	default:
 		return ""
 	}
@@ -0,0 +1,110 @@
+package scanner
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// UDP port states. Open means a reply was received; OpenFiltered means no
+// reply and no ICMP unreachable arrived within the timeout (ambiguous,
+// the classic UDP-scan caveat); Closed means an ICMP port-unreachable was
+// observed.
+const (
+	udpOpen         = "open"
+	udpOpenFiltered = "open|filtered"
+	udpClosed       = "closed"
+)
+
+// ScanHostPortsUDP scans ports over UDP using protocol-specific probe
+// payloads (see udp_payloads.go). It uses a raw ICMP listener to detect
+// port-unreachable replies when the process has permission to open one;
+// otherwise a port with no reply is reported open|filtered rather than
+// closed, since a silently-dropped UDP probe is indistinguishable from an
+// open service that ignores malformed input.
+func ScanHostPortsUDP(host string, ports []int, timeout time.Duration, workers int, probe bool) []Result {
+	if workers <= 0 {
+		workers = 100
+	}
+
+	icmp := newICMPUnreachableListener(host)
+	defer icmp.Close()
+
+	jobs := make(chan int)
+	results := make(chan Result)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				results <- probeUDPPort(host, p, timeout, icmp, probe)
+			}
+		}()
+	}
+
+	go func() {
+		for _, p := range ports {
+			jobs <- p
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	out := make([]Result, 0, len(ports))
+	for r := range results {
+		out = append(out, r)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Port < out[j].Port })
+	return out
+}
+
+func probeUDPPort(host string, port int, timeout time.Duration, icmp *icmpUnreachableListener, probe bool) Result {
+	start := time.Now()
+	addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+	conn, err := net.DialTimeout("udp", addr, timeout)
+	if err != nil {
+		return Result{Host: host, Port: port, Proto: "udp", State: udpClosed, Latency: time.Since(start), Err: err.Error()}
+	}
+	defer conn.Close()
+
+	payload, parse := udpPayloadFor(port)
+	unreachable := icmp.Wait(port)
+
+	if _, err := conn.Write(payload); err != nil {
+		return Result{Host: host, Port: port, Proto: "udp", State: udpOpenFiltered, Latency: time.Since(start)}
+	}
+
+	replyCh := make(chan []byte, 1)
+	go func() {
+		_ = conn.SetReadDeadline(time.Now().Add(timeout))
+		buf := make([]byte, 4096)
+		n, err := conn.Read(buf)
+		if err != nil {
+			replyCh <- nil
+			return
+		}
+		replyCh <- buf[:n]
+	}()
+
+	select {
+	case data := <-replyCh:
+		if data == nil {
+			return Result{Host: host, Port: port, Proto: "udp", State: udpOpenFiltered, Latency: time.Since(start)}
+		}
+		fp := ""
+		if probe && parse != nil {
+			fp = parse(data)
+		}
+		return Result{Host: host, Port: port, Proto: "udp", Open: true, State: udpOpen, Latency: time.Since(start), Service: knownServiceForPort(port), Fingerprint: fp}
+	case <-unreachable:
+		return Result{Host: host, Port: port, Proto: "udp", State: udpClosed, Latency: time.Since(start)}
+	case <-time.After(timeout):
+		return Result{Host: host, Port: port, Proto: "udp", State: udpOpenFiltered, Latency: time.Since(start)}
+	}
+}
@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+)
+
+const (
+	dnsClassIN    = 1
+	dnsClassCHAOS = 3
+	dnsTypeTXT    = 16
+	dnsTypePTR    = 12
+)
+
+// dnsVersionBindQuery builds a standard query for TXT version.bind in the
+// CHAOS class, the conventional way to fingerprint BIND's version string.
+func dnsVersionBindQuery() []byte {
+	return dnsQuery("version.bind", dnsTypeTXT, dnsClassCHAOS)
+}
+
+// mdnsServicesQuery builds an mDNS query for the generic service
+// enumeration PTR record.
+func mdnsServicesQuery() []byte {
+	return dnsQuery("_services._dns-sd._udp.local", dnsTypePTR, dnsClassIN)
+}
+
+func dnsQuery(name string, qtype, qclass uint16) []byte {
+	buf := new(bytes.Buffer)
+	id := uint16(rand.Intn(0xFFFF))
+	writeUint16BE(buf, id)
+	writeUint16BE(buf, 0x0100) // standard query, recursion desired
+	writeUint16BE(buf, 1)      // QDCOUNT
+	writeUint16BE(buf, 0)      // ANCOUNT
+	writeUint16BE(buf, 0)      // NSCOUNT
+	writeUint16BE(buf, 0)      // ARCOUNT
+	buf.Write(dnsEncodeName(name))
+	writeUint16BE(buf, qtype)
+	writeUint16BE(buf, qclass)
+	return buf.Bytes()
+}
+
+func dnsEncodeName(name string) []byte {
+	buf := new(bytes.Buffer)
+	for _, label := range strings.Split(name, ".") {
+		buf.WriteByte(byte(len(label)))
+		buf.WriteString(label)
+	}
+	buf.WriteByte(0)
+	return buf.Bytes()
+}
+
+func writeUint16BE(buf *bytes.Buffer, v uint16) {
+	buf.WriteByte(byte(v >> 8))
+	buf.WriteByte(byte(v))
+}
+
+// parseDNSReply returns the data of the first answer record: the first
+// character-string of a TXT record or the decoded name of a PTR record.
+// Any other record type, or a reply with no answers, yields "".
+func parseDNSReply(msg []byte) string {
+	if len(msg) < 12 {
+		return ""
+	}
+	ancount := int(msg[6])<<8 | int(msg[7])
+	if ancount == 0 {
+		return ""
+	}
+	pos := 12
+	// Skip the question section.
+	var ok bool
+	pos, ok = dnsSkipName(msg, pos)
+	if !ok || pos+4 > len(msg) {
+		return ""
+	}
+	pos += 4 // QTYPE + QCLASS
+
+	pos, ok = dnsSkipName(msg, pos) // answer's owner name
+	if !ok || pos+10 > len(msg) {
+		return ""
+	}
+	rtype := int(msg[pos])<<8 | int(msg[pos+1])
+	rdlen := int(msg[pos+8])<<8 | int(msg[pos+9])
+	rdataStart := pos + 10
+	if rdataStart+rdlen > len(msg) {
+		return ""
+	}
+	rdata := msg[rdataStart : rdataStart+rdlen]
+
+	switch rtype {
+	case dnsTypeTXT:
+		if len(rdata) > 0 {
+			l := int(rdata[0])
+			if 1+l <= len(rdata) {
+				return string(rdata[1 : 1+l])
+			}
+		}
+	case dnsTypePTR:
+		if name, ok := dnsReadName(msg, rdataStart); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// dnsSkipName advances past a (possibly compressed) name starting at pos
+// and returns the position immediately after it.
+func dnsSkipName(msg []byte, pos int) (int, bool) {
+	for pos < len(msg) {
+		l := int(msg[pos])
+		switch {
+		case l == 0:
+			return pos + 1, true
+		case l&0xC0 == 0xC0:
+			return pos + 2, true
+		default:
+			pos += 1 + l
+		}
+	}
+	return 0, false
+}
+
+// dnsReadName decodes a (possibly compressed) name starting at pos.
+func dnsReadName(msg []byte, pos int) (string, bool) {
+	var parts []string
+	visited := 0
+	for pos < len(msg) && visited < 20 {
+		visited++
+		l := int(msg[pos])
+		switch {
+		case l == 0:
+			return strings.Join(parts, "."), true
+		case l&0xC0 == 0xC0:
+			if pos+1 >= len(msg) {
+				return "", false
+			}
+			pos = int(msg[pos]&0x3F)<<8 | int(msg[pos+1])
+		default:
+			if pos+1+l > len(msg) {
+				return "", false
+			}
+			parts = append(parts, string(msg[pos+1:pos+1+l]))
+			pos += 1 + l
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// ssdpMSearch is the standard SSDP discovery request; devices answer
+// with an HTTP-like response carrying SERVER/LOCATION headers.
+const ssdpMSearch = "M-SEARCH * HTTP/1.1\r\n" +
+	"HOST: 239.255.255.250:1900\r\n" +
+	"MAN: \"ssdp:discover\"\r\n" +
+	"MX: 1\r\n" +
+	"ST: ssdp:all\r\n\r\n"
+
+// parseSSDPReply pulls the SERVER header out of an SSDP response.
+func parseSSDPReply(resp []byte) string {
+	r := bufio.NewReader(bytes.NewReader(resp))
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return ""
+		}
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(strings.ToLower(line), "server:") {
+			return strings.TrimSpace(line[len("server:"):])
+		}
+	}
+}
+
+// ikeSAInitProbe builds a bare ISAKMP header (no payloads) as a v1
+// SA_INIT probe; a well-formed response header alone is enough to
+// confirm an IKE listener is present.
+func ikeSAInitProbe() []byte {
+	hdr := make([]byte, 28)
+	// Initiator cookie: non-zero so the packet isn't dropped as garbage.
+	copy(hdr[0:8], []byte{0x13, 0x37, 0xBE, 0xEF, 0xCA, 0xFE, 0xF0, 0x0D})
+	hdr[16] = 0   // Next Payload: none
+	hdr[17] = 0x10 // Version: IKEv1 (major 1, minor 0)
+	hdr[18] = 0    // Exchange Type
+	hdr[19] = 0    // Flags
+	// Message ID (20:24) and Length (24:28) left zero.
+	return hdr
+}
+
+// parseIKEReply reports whether the reply looks like an ISAKMP header.
+func parseIKEReply(resp []byte) string {
+	if len(resp) < 28 {
+		return ""
+	}
+	return "ISAKMP responder present"
+}
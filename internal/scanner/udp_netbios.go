@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+)
+
+const netbiosStatTypeNBSTAT = 0x21
+
+// netbiosStatusQuery builds a NetBIOS Name Service NBSTAT query for the
+// wildcard name "*", used to enumerate the names a host has registered.
+func netbiosStatusQuery() []byte {
+	buf := make([]byte, 0, 50)
+	buf = append(buf, 0x00, 0x00) // TransactionID
+	buf = append(buf, 0x00, 0x00) // Flags: query
+	buf = append(buf, 0x00, 0x01) // QDCOUNT
+	buf = append(buf, 0x00, 0x00) // ANCOUNT
+	buf = append(buf, 0x00, 0x00) // NSCOUNT
+	buf = append(buf, 0x00, 0x00) // ARCOUNT
+	buf = append(buf, netbiosEncodeName("*")...)
+	buf = append(buf, 0x00, netbiosStatTypeNBSTAT) // QTYPE: NBSTAT
+	buf = append(buf, 0x00, 0x01)                  // QCLASS: IN
+	return buf
+}
+
+// netbiosEncodeName applies the RFC 1002 "half-ASCII" encoding: the
+// (padded, 16-byte) NetBIOS name is split into nibbles, each mapped to
+// 'A'..'P'.
+func netbiosEncodeName(name string) []byte {
+	padded := make([]byte, 16)
+	copy(padded, name)
+	for i := len(name); i < 16; i++ {
+		padded[i] = ' '
+	}
+	out := make([]byte, 0, 34)
+	out = append(out, 32) // length prefix: 32 encoded bytes follow
+	for _, b := range padded {
+		out = append(out, 'A'+(b>>4), 'A'+(b&0x0F))
+	}
+	out = append(out, 0) // name terminator
+	return out
+}
+
+// parseNetBIOSStatusReply decodes the NBSTAT response's name table into
+// a short comma-separated summary of registered names.
+func parseNetBIOSStatusReply(msg []byte) string {
+	// Skip header (12) + the encoded question name (34) + QTYPE/QCLASS (4)
+	// + the answer's name (2, a compression pointer) + TYPE/CLASS/TTL (8)
+	// + RDLENGTH (2) to reach the RDATA, whose first byte is NUM_NAMES.
+	const hdrLen = 12
+	pos := hdrLen + 34 + 4 + 2 + 8 + 2
+	if pos >= len(msg) {
+		return ""
+	}
+	numNames := int(msg[pos])
+	pos++
+
+	var names []string
+	for i := 0; i < numNames && pos+18 <= len(msg); i++ {
+		name := strings.TrimRight(string(msg[pos:pos+15]), " ")
+		if name != "" {
+			names = append(names, name)
+		}
+		pos += 18
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	if len(names) > 3 {
+		return fmt.Sprintf("%s (+%d more)", strings.Join(names[:3], ","), len(names)-3)
+	}
+	return strings.Join(names, ",")
+}
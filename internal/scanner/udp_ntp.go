@@ -0,0 +1,19 @@
+package scanner
+
+import "fmt"
+
+// ntpClientRequest builds a minimal NTP v3 mode-3 (client) request: a
+// 48-byte packet with only the first byte set (LI=0, VN=3, Mode=3).
+func ntpClientRequest() []byte {
+	req := make([]byte, 48)
+	req[0] = 0x1B
+	return req
+}
+
+// parseNTPReply reports the server's stratum from an NTP reply.
+func parseNTPReply(resp []byte) string {
+	if len(resp) < 2 {
+		return ""
+	}
+	return fmt.Sprintf("stratum=%d", resp[1])
+}
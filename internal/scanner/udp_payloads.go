@@ -0,0 +1,27 @@
+package scanner
+
+// udpProbe pairs a probe payload for a well-known UDP service with a
+// parser that extracts a human-readable fingerprint from the reply.
+type udpProbe struct {
+	Payload []byte
+	Parse   func([]byte) string
+}
+
+var udpProbeTable = map[int]udpProbe{
+	53:   {Payload: dnsVersionBindQuery(), Parse: parseDNSReply},
+	123:  {Payload: ntpClientRequest(), Parse: parseNTPReply},
+	137:  {Payload: netbiosStatusQuery(), Parse: parseNetBIOSStatusReply},
+	161:  {Payload: snmpGetSysDescr(), Parse: parseSNMPSysDescr},
+	500:  {Payload: ikeSAInitProbe(), Parse: parseIKEReply},
+	1900: {Payload: []byte(ssdpMSearch), Parse: parseSSDPReply},
+	5353: {Payload: mdnsServicesQuery(), Parse: parseDNSReply},
+}
+
+// udpPayloadFor returns the probe payload and parser for port, or a
+// generic zero-byte probe with no parser for anything not in the table.
+func udpPayloadFor(port int) ([]byte, func([]byte) string) {
+	if p, ok := udpProbeTable[port]; ok {
+		return p.Payload, p.Parse
+	}
+	return []byte{}, nil
+}
@@ -0,0 +1,225 @@
+package scanner
+
+import (
+	"bytes"
+	"math"
+)
+
+// A tiny subset of BER/DER encoding, just enough to build a fixed
+// SNMPv2c GetRequest for sysDescr.0 and to read the OCTET STRING value
+// back out of the response.
+
+const (
+	berInteger       = 0x02
+	berOctetString   = 0x04
+	berNull          = 0x05
+	berObjectID      = 0x06
+	berSequence      = 0x30
+	snmpGetRequestPDU = 0xA0
+)
+
+func berTLV(tag byte, content []byte) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(tag)
+	buf.Write(berLength(len(content)))
+	buf.Write(content)
+	return buf.Bytes()
+}
+
+// berLength encodes n in the short form when it is below 128, and in the
+// long form (0x80|count, then the length's big-endian bytes) otherwise.
+func berLength(n int) []byte {
+	if n < 128 {
+		return []byte{byte(n)}
+	}
+	var b []byte
+	for n > 0 {
+		b = append([]byte{byte(n & 0xFF)}, b...)
+		n >>= 8
+	}
+	return append([]byte{byte(0x80 | len(b))}, b...)
+}
+
+func berInt(v int) []byte {
+	if v == 0 {
+		return berTLV(berInteger, []byte{0})
+	}
+	var b []byte
+	for v > 0 {
+		b = append([]byte{byte(v & 0xFF)}, b...)
+		v >>= 8
+	}
+	if b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	return berTLV(berInteger, b)
+}
+
+func berOID(ids []int) []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(berEncodeBase128(ids[0]*40 + ids[1]))
+	for _, id := range ids[2:] {
+		buf.Write(berEncodeBase128(id))
+	}
+	return berTLV(berObjectID, buf.Bytes())
+}
+
+func berEncodeBase128(v int) []byte {
+	if v == 0 {
+		return []byte{0}
+	}
+	var groups []byte
+	for v > 0 {
+		groups = append([]byte{byte(v & 0x7F)}, groups...)
+		v >>= 7
+	}
+	for i := 0; i < len(groups)-1; i++ {
+		groups[i] |= 0x80
+	}
+	return groups
+}
+
+// snmpGetSysDescr builds an SNMPv2c GetRequest for 1.3.6.1.2.1.1.1.0
+// (sysDescr.0) using the default "public" community.
+func snmpGetSysDescr() []byte {
+	oid := berOID([]int{1, 3, 6, 1, 2, 1, 1, 1, 0})
+	varBind := berTLV(berSequence, append(oid, berTLV(berNull, nil)...))
+	varBindList := berTLV(berSequence, varBind)
+
+	pdu := berTLV(snmpGetRequestPDU, concatBytes(
+		berInt(1), // request-id
+		berInt(0), // error-status
+		berInt(0), // error-index
+		varBindList,
+	))
+
+	msg := berTLV(berSequence, concatBytes(
+		berInt(1), // SNMP version: v2c
+		berTLV(berOctetString, []byte("public")),
+		pdu,
+	))
+	return msg
+}
+
+func concatBytes(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+// parseSNMPSysDescr walks the fixed GetResponse-PDU layout far enough to
+// pull out the sysDescr OCTET STRING value.
+func parseSNMPSysDescr(msg []byte) string {
+	pos := 0
+	_, _, content, ok := berRead(msg, pos) // outer SEQUENCE
+	if !ok {
+		return ""
+	}
+	pos = 0
+	_, next, ok := berSkip(content, pos) // version
+	if !ok {
+		return ""
+	}
+	pos = next
+	_, next, ok = berSkip(content, pos) // community
+	if !ok {
+		return ""
+	}
+	pos = next
+	_, pduBody, ok := berReadAt(content, pos) // PDU (any context tag)
+	if !ok {
+		return ""
+	}
+
+	pos = 0
+	_, next, ok = berSkip(pduBody, pos) // request-id
+	if !ok {
+		return ""
+	}
+	pos = next
+	_, next, ok = berSkip(pduBody, pos) // error-status
+	if !ok {
+		return ""
+	}
+	pos = next
+	_, next, ok = berSkip(pduBody, pos) // error-index
+	if !ok {
+		return ""
+	}
+	pos = next
+
+	_, varBindList, ok := berReadAt(pduBody, pos)
+	if !ok {
+		return ""
+	}
+	_, varBind, ok := berReadAt(varBindList, 0)
+	if !ok {
+		return ""
+	}
+	_, next, ok = berSkip(varBind, 0) // OID
+	if !ok {
+		return ""
+	}
+	tag, value, ok := berReadAt(varBind, next)
+	if !ok || tag != berOctetString {
+		return ""
+	}
+	return string(value)
+}
+
+// berRead reads one TLV at pos and returns its tag, content, and the
+// position immediately after it.
+func berRead(b []byte, pos int) (tag byte, next int, content []byte, ok bool) {
+	if pos >= len(b) {
+		return 0, 0, nil, false
+	}
+	tag = b[pos]
+	length, lenBytes, ok := berReadLength(b, pos+1)
+	if !ok || pos+1+lenBytes+length > len(b) {
+		return 0, 0, nil, false
+	}
+	start := pos + 1 + lenBytes
+	return tag, start + length, b[start : start+length], true
+}
+
+func berReadAt(b []byte, pos int) (tag byte, content []byte, ok bool) {
+	tag, _, content, ok = berRead(b, pos)
+	return
+}
+
+func berSkip(b []byte, pos int) (tag byte, next int, ok bool) {
+	tag, next, _, ok = berRead(b, pos)
+	return
+}
+
+func berReadLength(b []byte, pos int) (length int, consumed int, ok bool) {
+	if pos >= len(b) {
+		return 0, 0, false
+	}
+	first := b[pos]
+	if first&0x80 == 0 {
+		return int(first), 1, true
+	}
+	n := int(first & 0x7F)
+	// Reject indefinite-length (n==0, unsupported here) and anything
+	// longer than 4 length bytes: the messages this scanner parses never
+	// need more than 32 bits of length, and allowing more risks
+	// overflowing length to a negative int, which would defeat every
+	// bounds check a caller makes against it.
+	if n == 0 || n > 4 {
+		return 0, 0, false
+	}
+	if pos+1+n > len(b) {
+		return 0, 0, false
+	}
+	var l uint32
+	for i := 0; i < n; i++ {
+		l = l<<8 | uint32(b[pos+1+i])
+	}
+	if l > math.MaxInt32 {
+		return 0, 0, false
+	}
+	return int(l), 1 + n, true
+}
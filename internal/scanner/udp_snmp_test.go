@@ -0,0 +1,130 @@
+package scanner
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBERLengthRoundTrip(t *testing.T) {
+	cases := []int{0, 1, 127, 128, 255, 256, 65535, 70000}
+	for _, n := range cases {
+		encoded := berLength(n)
+		got, consumed, ok := berReadLength(encoded, 0)
+		if !ok {
+			t.Fatalf("berReadLength(%v) for n=%d: not ok", encoded, n)
+		}
+		if got != n {
+			t.Errorf("berLength(%d) round-tripped to %d", n, got)
+		}
+		if consumed != len(encoded) {
+			t.Errorf("berLength(%d): consumed %d, want %d", n, consumed, len(encoded))
+		}
+	}
+}
+
+func TestBEROIDRoundTrip(t *testing.T) {
+	oid := []int{1, 3, 6, 1, 2, 1, 1, 1, 0}
+	encoded := berOID(oid)
+	tag, _, content, ok := berRead(encoded, 0)
+	if !ok {
+		t.Fatalf("berRead(berOID(%v)): not ok", oid)
+	}
+	if tag != berObjectID {
+		t.Errorf("tag = 0x%02x, want berObjectID", tag)
+	}
+
+	// Decode the base-128 identifiers back out and compare against oid,
+	// with the first two arcs collapsed the same way berOID combines them.
+	var got []int
+	val := 0
+	for _, b := range content {
+		val = val<<7 | int(b&0x7F)
+		if b&0x80 == 0 {
+			got = append(got, val)
+			val = 0
+		}
+	}
+	want := []int{oid[0]*40 + oid[1]}
+	want = append(want, oid[2:]...)
+	if len(got) != len(want) {
+		t.Fatalf("decoded arcs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("arc %d = %d, want %d", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBERIntRoundTrip(t *testing.T) {
+	cases := []int{0, 1, 127, 128, 255, 256, 65535}
+	for _, v := range cases {
+		encoded := berInt(v)
+		tag, _, content, ok := berRead(encoded, 0)
+		if !ok || tag != berInteger {
+			t.Fatalf("berRead(berInt(%d)): tag=0x%02x ok=%v", v, tag, ok)
+		}
+		got := 0
+		for _, b := range content {
+			got = got<<8 | int(b)
+		}
+		if got != v {
+			t.Errorf("berInt(%d) round-tripped to %d", v, got)
+		}
+	}
+}
+
+func TestParseSNMPSysDescrRoundTrip(t *testing.T) {
+	want := "test-device v1.0"
+
+	oid := berOID([]int{1, 3, 6, 1, 2, 1, 1, 1, 0})
+	varBind := berTLV(berSequence, append(oid, berTLV(berOctetString, []byte(want))...))
+	varBindList := berTLV(berSequence, varBind)
+	pdu := berTLV(0xA2, concatBytes( // GetResponse-PDU
+		berInt(1), // request-id
+		berInt(0), // error-status
+		berInt(0), // error-index
+		varBindList,
+	))
+	msg := berTLV(berSequence, concatBytes(
+		berInt(1),
+		berTLV(berOctetString, []byte("public")),
+		pdu,
+	))
+
+	got := parseSNMPSysDescr(msg)
+	if got != want {
+		t.Errorf("parseSNMPSysDescr() = %q, want %q", got, want)
+	}
+}
+
+func TestBERReadTruncated(t *testing.T) {
+	// A length byte claiming more content than is actually present must
+	// fail cleanly rather than panic or slice out of range.
+	msg := []byte{berOctetString, 0x10, 'h', 'i'}
+	if _, _, _, ok := berRead(msg, 0); ok {
+		t.Error("berRead on truncated content: got ok=true, want false")
+	}
+}
+
+func TestBERReadLengthOverflow(t *testing.T) {
+	// A long-form length with 8 0xFF bytes overflows int32 and, if not
+	// guarded, wraps to a negative length that bypasses the bounds check
+	// in berRead and panics on the subsequent slice.
+	msg := []byte{berOctetString, 0x88, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 'h', 'i'}
+	if _, _, ok := berReadLength(msg, 1); ok {
+		t.Error("berReadLength on an 8-byte 0xFF length: got ok=true, want false")
+	}
+	if _, _, _, ok := berRead(msg, 0); ok {
+		t.Error("berRead on an 8-byte 0xFF length: got ok=true, want false")
+	}
+}
+
+func TestBERTLVEncodesTagAndLength(t *testing.T) {
+	content := []byte("public")
+	tlv := berTLV(berOctetString, content)
+	want := append([]byte{berOctetString, byte(len(content))}, content...)
+	if !bytes.Equal(tlv, want) {
+		t.Errorf("berTLV(berOctetString, %q) = % x, want % x", content, tlv, want)
+	}
+}
@@ -0,0 +1,52 @@
+package scanner
+
+import "time"
+
+// Vuln describes a single finding surfaced by a VulnProbe against an
+// already-identified service.
+type Vuln struct {
+	ID          string `json:"id"`
+	Severity    string `json:"severity"`
+	Evidence    string `json:"evidence,omitempty"`
+	Description string `json:"description"`
+}
+
+// VulnProbe performs a targeted, read-only check for a specific
+// vulnerability against a service that fingerprintService (or
+// knownServiceForPort) has already identified.
+type VulnProbe interface {
+	// ID is the probe's short identifier, typically a CVE or advisory name.
+	ID() string
+	// Check dials host:port itself and returns a Vuln if the target looks
+	// vulnerable. A nil Vuln with a nil error means the probe completed
+	// cleanly and found nothing.
+	Check(host string, port int, timeout time.Duration) (*Vuln, error)
+}
+
+// vulnProbes maps a service name to the probes registered against it.
+var vulnProbes = map[string][]VulnProbe{}
+
+// registerVulnProbe adds p to the registry for the given service name.
+func registerVulnProbe(service string, p VulnProbe) {
+	vulnProbes[service] = append(vulnProbes[service], p)
+}
+
+// runVulnProbes runs every probe registered for service, time-boxed by the
+// scan's existing per-port timeout. Probe errors (closed port, reset,
+// timeout) are swallowed rather than surfaced, since a probe that can't
+// complete simply contributes no finding.
+func runVulnProbes(service, host string, port int, timeout time.Duration) []Vuln {
+	probes := vulnProbes[service]
+	if len(probes) == 0 {
+		return nil
+	}
+	var vulns []Vuln
+	for _, p := range probes {
+		v, err := p.Check(host, port, timeout)
+		if err != nil || v == nil {
+			continue
+		}
+		vulns = append(vulns, *v)
+	}
+	return vulns
+}